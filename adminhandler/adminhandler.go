@@ -0,0 +1,651 @@
+package adminhandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+)
+
+const (
+	defaultMaxBodyBytes = 1 << 20
+)
+
+var (
+	ErrRequestBody = errors.New("adminhandler: the request body is invalid")
+	ErrNotFound    = errors.New("adminhandler: the resource is not found")
+	ErrMethod      = errors.New("adminhandler: the method is not allowed")
+	ErrContentType = errors.New("adminhandler: the content type is not application/json")
+	ErrCrossSite   = errors.New("adminhandler: the request is cross-site")
+)
+
+var (
+	listTypes = map[string]scheduler.ListType{
+		"table":    scheduler.ListTypeDefault,
+		"json":     scheduler.ListTypeJSON,
+		"csv":      scheduler.ListTypeCSV,
+		"yaml":     scheduler.ListTypeYAML,
+		"markdown": scheduler.ListTypeMarkdown,
+		"html":     scheduler.ListTypeHTML,
+		"ical":     scheduler.ListTypeICal,
+	}
+
+	contentTypes = map[string]string{
+		"table":    "text/plain; charset=utf-8",
+		"json":     "application/json",
+		"csv":      "text/csv; charset=utf-8",
+		"yaml":     "application/yaml",
+		"markdown": "text/markdown; charset=utf-8",
+		"html":     "text/html; charset=utf-8",
+		"ical":     "text/calendar; charset=utf-8",
+	}
+)
+
+type Config struct {
+	// MaxBodyBytes bounds the request bodies, default to 1 MiB.
+	MaxBodyBytes int64
+
+	// Authenticators are tried in order until one finds its credentials in
+	// the request. The roles of the principal are looked up in Roles, a
+	// handler without Authenticators serves every request anonymously.
+	Authenticators []Authenticator
+	Roles          []Role
+
+	// Audit is called after every mutation with the principal making it.
+	Audit func(record AuditRecord)
+
+	// KeepAliveInterval is the interval of the comments keeping an idle
+	// event stream open, default to 15 seconds.
+	KeepAliveInterval time.Duration
+}
+
+// Handler serves the admin API of a scheduler:
+//
+//	GET    /jobs                  list the jobs, see Query
+//	POST   /jobs                  add a job bound to a registered handler
+//	GET    /jobs/{key}            get a job
+//	DELETE /jobs/{key}            cancel a job
+//	POST   /jobs/{key}/reschedule reschedule a job
+//	POST   /jobs/{key}/pause      pause a job
+//	POST   /jobs/{key}/resume     resume a job
+//	POST   /jobs/{key}/run        run a job now
+//	GET    /history               list the retained runs, see History
+//	GET    /events                stream the events as server-sent events
+//
+// Keys are path escaped, so a key containing a slash is sent as %2F.
+type Handler struct {
+	scheduler *scheduler.Scheduler
+	config    Config
+	roles     map[string]*Role
+}
+
+func NewHandler(s *scheduler.Scheduler, configs ...Config) *Handler {
+	config := Config{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	if config.KeepAliveInterval <= 0 {
+		config.KeepAliveInterval = defaultKeepAliveInterval
+	}
+
+	roles := make(map[string]*Role, len(config.Roles))
+	for i := range config.Roles {
+		roles[config.Roles[i].Name] = &config.Roles[i]
+	}
+
+	return &Handler{
+		scheduler: s,
+		config:    config,
+		roles:     roles,
+	}
+}
+
+// Job is the JSON representation of a job.
+type Job struct {
+	Key              string   `json:"key"`
+	Handler          string   `json:"handler,omitempty"`
+	DateTime         string   `json:"date_time"`
+	TimeZone         string   `json:"time_zone"`
+	RemainingSeconds float64  `json:"remaining_seconds"`
+	Status           string   `json:"status"`
+	RunCount         int      `json:"run_count"`
+	LastRun          *Run     `json:"last_run,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Trigger          string   `json:"trigger"`
+}
+
+type Run struct {
+	DateTime  time.Time `json:"date_time"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Attempt   int       `json:"attempt"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+	Output    string    `json:"output,omitempty"`
+}
+
+// CreateRequest adds a job at DateTime, or after Delay when DateTime is
+// empty. RRule makes the job recurring from DateTime.
+type CreateRequest struct {
+	Key      string   `json:"key"`
+	Handler  string   `json:"handler"`
+	DateTime string   `json:"date_time,omitempty"`
+	Delay    string   `json:"delay,omitempty"`
+	RRule    string   `json:"rrule,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// RescheduleRequest moves a job to DateTime, or after Delay when DateTime is
+// empty.
+type RescheduleRequest struct {
+	DateTime string `json:"date_time,omitempty"`
+	Delay    string `json:"delay,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func toJob(info *scheduler.JobInfo) *Job {
+	job := &Job{
+		Key:              info.Key,
+		Handler:          info.Handler,
+		DateTime:         info.DateTime.Format(time.RFC3339Nano),
+		TimeZone:         info.TimeZone,
+		RemainingSeconds: info.Remaining.Seconds(),
+		Status:           string(info.Status),
+		RunCount:         info.RunCount,
+		Tags:             info.Tags,
+		Trigger:          info.Trigger,
+	}
+
+	if run := info.LastRun; run != nil {
+		job.LastRun = toRun(run)
+	}
+	return job
+}
+
+func toRun(run *scheduler.Run) *Run {
+	res := &Run{
+		DateTime:  run.DateTime,
+		StartTime: run.StartTime,
+		EndTime:   run.EndTime,
+		Attempt:   run.Attempt,
+		Outcome:   string(run.Outcome),
+		Output:    run.Output,
+	}
+
+	if run.Err != nil {
+		res.Error = run.Err.Error()
+	}
+	return res
+}
+
+// StatusCode maps the errors of the scheduler to HTTP status codes.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, scheduler.ErrKeyIsNotExists), errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scheduler.ErrKeyIsExists),
+		errors.Is(err, scheduler.ErrKeyIsPaused),
+		errors.Is(err, scheduler.ErrKeyIsNotPaused),
+		errors.Is(err, scheduler.ErrKeyIsRunning):
+		return http.StatusConflict
+	case errors.Is(err, scheduler.ErrHandlerIsNotExists),
+		errors.Is(err, scheduler.ErrDateTimeLessThanNow),
+		errors.Is(err, scheduler.ErrTriggerIsExhausted),
+		errors.Is(err, scheduler.ErrRRuleFormat):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrRequestBody), errors.Is(err, scheduler.ErrQueryCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrMethod):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrContentType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrCrossSite):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, StatusCode(err), &errorResponse{Error: err.Error()})
+}
+
+// route splits the escaped path into the resource, and the key and the
+// action of a job.
+func route(r *http.Request) (resource, key, action string, err error) {
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/")
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	resource = segments[0]
+	if _, isExists := resourceEndpoints[resource]; isExists && len(segments) == 1 {
+		return
+	}
+
+	if resource != "jobs" || len(segments) > 3 {
+		err = ErrNotFound
+		return
+	}
+
+	if len(segments) > 1 {
+		if key, err = url.PathUnescape(segments[1]); err != nil || key == "" {
+			err = ErrNotFound
+			return
+		}
+	}
+
+	if len(segments) > 2 {
+		action = segments[2]
+	}
+	return
+}
+
+type endpoint struct {
+	method    string
+	operation Operation
+}
+
+var (
+	collectionEndpoints = []endpoint{{http.MethodGet, OperationList}, {http.MethodPost, OperationCreate}}
+	jobEndpoints        = []endpoint{{http.MethodGet, OperationGet}, {http.MethodDelete, OperationCancel}}
+	resourceEndpoints   = map[string][]endpoint{
+		"history": {{http.MethodGet, OperationHistory}},
+		"events":  {{http.MethodGet, OperationWatch}},
+	}
+	actionOperations = map[string]Operation{
+		"reschedule": OperationReschedule,
+		"pause":      OperationPause,
+		"resume":     OperationResume,
+		"run":        OperationRun,
+	}
+)
+
+// operation resolves the operation of a request, the methods of the route
+// are sent in the Allow header when the method does not match.
+func operation(w http.ResponseWriter, r *http.Request, resource, key, action string) (Operation, error) {
+	endpoints := jobEndpoints
+	switch {
+	case resource != "jobs":
+		endpoints = resourceEndpoints[resource]
+	case key == "":
+		endpoints = collectionEndpoints
+	case action != "":
+		op, isExists := actionOperations[action]
+		if !isExists {
+			return "", ErrNotFound
+		}
+		endpoints = []endpoint{{http.MethodPost, op}}
+	}
+
+	methods := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		if r.Method == e.method {
+			return e.operation, nil
+		}
+		methods = append(methods, e.method)
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	return "", ErrMethod
+}
+
+// checkSameSite rejects a mutation a browser could send from another site.
+// A form or a simple request cannot set the application/json content type
+// without a CORS preflight, which the handler does not answer, and
+// Sec-Fetch-Site is set by the browsers sending it.
+func checkSameSite(r *http.Request, op Operation) error {
+	if op.isRead() {
+		return nil
+	}
+
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		return ErrCrossSite
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return ErrContentType
+	}
+	return nil
+}
+
+// ServeHTTP authenticates the request, authorizes its operation on the key
+// and reports the mutations to Config.Audit. The key of a created job is
+// read from the body before it is authorized. A mutation is only served
+// with the application/json content type, even without a body, so a
+// browser cannot send it from another site.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resource, key, action, err := route(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	op, err := operation(w, r, resource, key, action)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err = checkSameSite(r, op); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	principal, err := h.authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="scheduler"`)
+		writeError(w, err)
+		return
+	}
+	r = r.WithContext(context.WithValue(r.Context(), principalKey{}, principal))
+
+	var (
+		req     *CreateRequest
+		created *scheduler.JobInfo
+	)
+	if op == OperationCreate {
+		if req, err = h.decodeCreate(r); err != nil {
+			writeError(w, err)
+			return
+		}
+		key = req.Key
+	}
+
+	if !h.authorize(principal, op, key) {
+		writeError(w, ErrForbidden)
+		return
+	}
+
+	switch op {
+	case OperationList:
+		h.list(w, r)
+		return
+	case OperationGet:
+		h.writeJob(w, http.StatusOK, key)
+		return
+	case OperationHistory:
+		h.history(w, r)
+		return
+	case OperationWatch:
+		h.watch(w, r)
+		return
+	case OperationCreate:
+		created, err = h.create(req)
+	case OperationCancel:
+		err = h.scheduler.Cancel(key)
+	case OperationReschedule:
+		err = h.reschedule(r, key)
+	case OperationPause:
+		err = h.scheduler.Pause(key)
+	case OperationResume:
+		err = h.scheduler.Resume(key)
+	case OperationRun:
+		err = h.scheduler.RunNow(key)
+	}
+
+	h.audit(r, op, key, err)
+	switch {
+	case err != nil:
+		writeError(w, err)
+	case op == OperationCancel:
+		w.WriteHeader(http.StatusNoContent)
+	case op == OperationCreate:
+		h.writeCreated(w, created)
+	case op == OperationRun:
+		h.writeJob(w, http.StatusAccepted, key)
+	default:
+		h.writeJob(w, http.StatusOK, key)
+	}
+}
+
+func (h *Handler) writeJob(w http.ResponseWriter, code int, key string) {
+	info, err := h.scheduler.Get(key)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, code, toJob(info))
+}
+
+func (h *Handler) decode(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, h.config.MaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return errors.Join(ErrRequestBody, err)
+	}
+	return nil
+}
+
+func parseDateTime(dateTime, delay string) (at time.Time, after time.Duration, err error) {
+	switch {
+	case dateTime != "":
+		at, err = time.Parse(time.RFC3339, dateTime)
+	case delay != "":
+		after, err = time.ParseDuration(delay)
+	default:
+		err = errors.New("date_time or delay is required")
+	}
+
+	if err != nil {
+		err = errors.Join(ErrRequestBody, err)
+	}
+	return
+}
+
+func (h *Handler) decodeCreate(r *http.Request) (*CreateRequest, error) {
+	req := &CreateRequest{}
+	if err := h.decode(r, req); err != nil {
+		return nil, err
+	}
+
+	if req.Key == "" || req.Handler == "" {
+		return nil, errors.Join(ErrRequestBody, errors.New("key and handler are required"))
+	}
+	return req, nil
+}
+
+// create adds the job of req and returns it as described by req, a job
+// handed to Config.Store cannot be read back from the scheduler.
+func (h *Handler) create(req *CreateRequest) (info *scheduler.JobInfo, err error) {
+	at, after, err := parseDateTime(req.DateTime, req.Delay)
+	if err != nil {
+		return
+	}
+
+	dateTime := at
+	if dateTime.IsZero() {
+		dateTime = time.Now().Add(after)
+	}
+
+	info = &scheduler.JobInfo{
+		Key:       req.Key,
+		Handler:   req.Handler,
+		DateTime:  dateTime,
+		TimeZone:  dateTime.Location().String(),
+		Remaining: time.Until(dateTime),
+		Status:    scheduler.JobStatusPending,
+		Tags:      req.Tags,
+		Trigger:   "once at " + dateTime.Format(time.RFC3339),
+	}
+
+	opt := scheduler.JobOption{Tags: req.Tags}
+	switch {
+	case req.RRule != "":
+		var rrule *scheduler.RRule
+		if rrule, err = scheduler.ParseRRule(dateTime, req.RRule); err != nil {
+			return
+		}
+
+		info.Recurrence, info.Trigger = rrule, rrule.String()
+		err = h.scheduler.AddTriggerHandler(req.Key, rrule, req.Handler, opt)
+	case !at.IsZero():
+		err = h.scheduler.AddDateHandler(req.Key, at, req.Handler, opt)
+	default:
+		err = h.scheduler.AddHandler(req.Key, after, req.Handler, opt)
+	}
+	return
+}
+
+// writeCreated writes the job as kept by the scheduler, or as described by
+// the request when the scheduler does not keep it.
+func (h *Handler) writeCreated(w http.ResponseWriter, info *scheduler.JobInfo) {
+	if scheduled, err := h.scheduler.Get(info.Key); err == nil {
+		info = scheduled
+	}
+
+	writeJSON(w, http.StatusCreated, toJob(info))
+}
+
+func (h *Handler) reschedule(r *http.Request, key string) error {
+	req := &RescheduleRequest{}
+	if err := h.decode(r, req); err != nil {
+		return err
+	}
+
+	at, after, err := parseDateTime(req.DateTime, req.Delay)
+	if err != nil {
+		return err
+	}
+
+	if !at.IsZero() {
+		return h.scheduler.RescheduleDateTime(key, at)
+	}
+	return h.scheduler.Reschedule(key, after)
+}
+
+// list renders the jobs with the converter named by the format parameter,
+// default to the JSON format of List, or as Job with the detail format. The
+// cursor of the next page is sent in the X-Next-Cursor header, the jobs the
+// principal may not list are left out of the page.
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	opt, err := toQueryOption(values)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	format := values.Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	listType, isExists := listTypes[format]
+	if !isExists && format != "detail" {
+		writeError(w, errors.Join(ErrRequestBody, errors.New("unknown format "+format)))
+		return
+	}
+
+	res, err := h.scheduler.Query(opt)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	principal, _ := PrincipalFromContext(r.Context())
+	infos := make([]*scheduler.JobInfo, 0, len(res.Jobs))
+	for _, info := range res.Jobs {
+		if h.authorize(principal, OperationList, info.Key) {
+			infos = append(infos, info)
+		}
+	}
+
+	if res.Cursor != "" {
+		w.Header().Set("X-Next-Cursor", res.Cursor)
+	}
+
+	if format == "detail" {
+		jobs := make([]*Job, 0, len(infos))
+		for _, info := range infos {
+			jobs = append(jobs, toJob(info))
+		}
+
+		writeJSON(w, http.StatusOK, jobs)
+		return
+	}
+
+	data := make([]*scheduler.ResponseScheduler, 0, len(infos))
+	for _, info := range infos {
+		data = append(data, &scheduler.ResponseScheduler{
+			Key:        info.Key,
+			Time:       info.DateTime,
+			Handler:    info.Handler,
+			Status:     info.Status,
+			Tags:       info.Tags,
+			Recurrence: info.Recurrence,
+		})
+	}
+
+	bytes, err := scheduler.NewListConverter(listType).Convert(data)
+	if err != nil {
+		w.Header().Del("X-Next-Cursor")
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypes[format])
+	_, _ = w.Write(bytes)
+}
+
+func toQueryOption(values url.Values) (opt scheduler.QueryOption, err error) {
+	opt = scheduler.QueryOption{
+		KeyPrefix: values.Get("prefix"),
+		KeyGlob:   values.Get("glob"),
+		Tags:      values["tag"],
+		Cursor:    values.Get("cursor"),
+	}
+
+	for _, status := range values["status"] {
+		opt.Statuses = append(opt.Statuses, scheduler.JobStatus(status))
+	}
+
+	switch values.Get("sort") {
+	case "", "date_time":
+	case "key":
+		opt.Sort = scheduler.QuerySortKey
+	default:
+		err = errors.Join(ErrRequestBody, errors.New("unknown sort "+values.Get("sort")))
+		return
+	}
+
+	for name, dst := range map[string]*time.Time{"from": &opt.From, "to": &opt.To} {
+		if value := values.Get(name); value != "" {
+			if *dst, err = time.Parse(time.RFC3339, value); err != nil {
+				err = errors.Join(ErrRequestBody, err)
+				return
+			}
+		}
+	}
+
+	if value := values.Get("limit"); value != "" {
+		if opt.Limit, err = strconv.Atoi(value); err != nil || opt.Limit < 0 {
+			err = errors.Join(ErrRequestBody, errors.New("invalid limit "+value))
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,233 @@
+package adminhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+var fn = func(ctx context.Context) {}
+
+func do(t *testing.T, server *httptest.Server, method, path, body string) (*http.Response, map[string]interface{}) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	assert.Nil(t, err)
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := server.Client().Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	var data map[string]interface{}
+	if strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
+		_ = json.NewDecoder(res.Body).Decode(&data)
+	}
+	return res, data
+}
+
+func newServer(t *testing.T) (*scheduler.Scheduler, *httptest.Server) {
+	schedule := scheduler.NewScheduler()
+	assert.Nil(t, schedule.RegisterHandler("handler", fn))
+	server := httptest.NewServer(NewHandler(schedule))
+	t.Cleanup(server.Close)
+	return schedule, server
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Create, get and cancel", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+
+			res, data := do(t, server, http.MethodPost, "/jobs", `{"key":"billing/1","handler":"handler","delay":"1h","tags":["billing"]}`)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Equal(t, "billing/1", data["key"])
+			assert.Equal(t, "pending", data["status"])
+			assert.Equal(t, []interface{}{"billing"}, data["tags"])
+
+			res, data = do(t, server, http.MethodGet, "/jobs/billing%2F1", "")
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "handler", data["handler"])
+			assert.InDelta(t, 3600, data["remaining_seconds"], 5)
+
+			res, _ = do(t, server, http.MethodDelete, "/jobs/billing%2F1", "")
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			_, err := schedule.Get("billing/1")
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, err)
+		})
+
+		t.Run("Create recurring job", func(t *testing.T) {
+			t.Parallel()
+			_, server := newServer(t)
+
+			res, data := do(t, server, http.MethodPost, "/jobs", `{"key":"report","handler":"handler","date_time":"2099-01-05T08:00:00Z","rrule":"FREQ=WEEKLY"}`)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Equal(t, "DTSTART:20990105T080000Z\nRRULE:FREQ=WEEKLY", data["trigger"])
+		})
+
+		t.Run("Create job handed to a store", func(t *testing.T) {
+			t.Parallel()
+			schedule := scheduler.NewScheduler(scheduler.Config{Store: scheduler.NewMemoryStore()})
+			t.Cleanup(schedule.Stop)
+			assert.Nil(t, schedule.RegisterHandler("handler", fn))
+			server := httptest.NewServer(NewHandler(schedule))
+			t.Cleanup(server.Close)
+
+			res, data := do(t, server, http.MethodPost, "/jobs", `{"key":"billing/1","handler":"handler","date_time":"2099-01-05T08:00:00Z","tags":["billing"]}`)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Equal(t, "billing/1", data["key"])
+			assert.Equal(t, "handler", data["handler"])
+			assert.Equal(t, "2099-01-05T08:00:00Z", data["date_time"])
+			assert.Equal(t, "pending", data["status"])
+			assert.Equal(t, []interface{}{"billing"}, data["tags"])
+			assert.Equal(t, "once at 2099-01-05T08:00:00Z", data["trigger"])
+		})
+
+		t.Run("Reschedule, pause, resume and run", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			schedule, server := newServer(t)
+			assert.Nil(t, schedule.Add("add#1", time.Hour, func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			}))
+
+			res, data := do(t, server, http.MethodPost, "/jobs/add%231/reschedule", `{"date_time":"2099-01-01T00:00:00Z"}`)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "2099-01-01T00:00:00Z", data["date_time"])
+
+			res, data = do(t, server, http.MethodPost, "/jobs/add%231/pause", "")
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "paused", data["status"])
+
+			res, data = do(t, server, http.MethodPost, "/jobs/add%231/resume", "")
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "pending", data["status"])
+
+			res, _ = do(t, server, http.MethodPost, "/jobs/add%231/run", "")
+			assert.Equal(t, http.StatusAccepted, res.StatusCode)
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&counter) == 1
+			}, time.Second, 5*time.Millisecond)
+		})
+
+		t.Run("List jobs", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			for _, key := range []string{"billing:1", "billing:2", "report:1"} {
+				assert.Nil(t, schedule.Add(key, time.Hour, fn))
+			}
+
+			res, err := server.Client().Get(server.URL + "/jobs?prefix=billing:&sort=key&limit=1")
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			var data []map[string]interface{}
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&data))
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Len(t, data, 1)
+			assert.Equal(t, "billing:1", data[0]["key"])
+			assert.NotEmpty(t, res.Header.Get("X-Next-Cursor"))
+
+			csv, err := server.Client().Get(server.URL + "/jobs?format=csv&sort=key")
+			assert.Nil(t, err)
+			defer csv.Body.Close()
+			assert.Equal(t, "text/csv; charset=utf-8", csv.Header.Get("Content-Type"))
+
+			detail, err := server.Client().Get(server.URL + "/jobs?format=detail&sort=key")
+			assert.Nil(t, err)
+			defer detail.Body.Close()
+
+			var jobs []*Job
+			assert.Nil(t, json.NewDecoder(detail.Body).Decode(&jobs))
+			assert.Len(t, jobs, 3)
+			assert.Equal(t, "pending", jobs[0].Status)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Map errors to status codes", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+
+			testCases := []struct {
+				method string
+				path   string
+				body   string
+				code   int
+			}{
+				{http.MethodPost, "/jobs", `{"key":"add#1","handler":"handler","delay":"1h"}`, http.StatusConflict},
+				{http.MethodPost, "/jobs", `{"key":"add#2","handler":"unknown","delay":"1h"}`, http.StatusUnprocessableEntity},
+				{http.MethodPost, "/jobs", `{"key":"add#2","handler":"handler","date_time":"2000-01-01T00:00:00Z"}`, http.StatusUnprocessableEntity},
+				{http.MethodPost, "/jobs", `{"key":"add#2","handler":"handler"}`, http.StatusBadRequest},
+				{http.MethodPost, "/jobs", `{"key":`, http.StatusBadRequest},
+				{http.MethodGet, "/jobs/unknown", "", http.StatusNotFound},
+				{http.MethodDelete, "/jobs/unknown", "", http.StatusNotFound},
+				{http.MethodPost, "/jobs/add%231/resume", "", http.StatusConflict},
+				{http.MethodPost, "/jobs/add%231/unknown", "", http.StatusNotFound},
+				{http.MethodGet, "/jobs/add%231/pause", "", http.StatusMethodNotAllowed},
+				{http.MethodPut, "/jobs", "", http.StatusMethodNotAllowed},
+				{http.MethodGet, "/jobs?cursor=!", "", http.StatusBadRequest},
+				{http.MethodGet, "/jobs?format=pdf", "", http.StatusBadRequest},
+				{http.MethodGet, "/other", "", http.StatusNotFound},
+			}
+
+			for _, testCase := range testCases {
+				res, data := do(t, server, testCase.method, testCase.path, testCase.body)
+				assert.Equal(t, testCase.code, res.StatusCode, testCase.method+" "+testCase.path)
+				assert.NotEmpty(t, data["error"], testCase.method+" "+testCase.path)
+			}
+		})
+
+		t.Run("Cross-site mutation", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			assert.Nil(t, schedule.Add("cross#1", time.Hour, fn))
+
+			testCases := []struct {
+				method      string
+				path        string
+				contentType string
+				fetchSite   string
+				code        int
+			}{
+				{http.MethodPost, "/jobs/cross%231/pause", "", "", http.StatusUnsupportedMediaType},
+				{http.MethodPost, "/jobs/cross%231/run", "text/plain", "", http.StatusUnsupportedMediaType},
+				{http.MethodDelete, "/jobs/cross%231", "application/x-www-form-urlencoded", "", http.StatusUnsupportedMediaType},
+				{http.MethodPost, "/jobs/cross%231/pause", "application/json", "cross-site", http.StatusForbidden},
+				{http.MethodGet, "/jobs/cross%231", "", "cross-site", http.StatusOK},
+				{http.MethodPost, "/jobs/cross%231/pause", "application/json; charset=utf-8", "same-origin", http.StatusOK},
+			}
+
+			for _, testCase := range testCases {
+				req, err := http.NewRequest(testCase.method, server.URL+testCase.path, nil)
+				assert.Nil(t, err)
+				if testCase.contentType != "" {
+					req.Header.Set("Content-Type", testCase.contentType)
+				}
+				if testCase.fetchSite != "" {
+					req.Header.Set("Sec-Fetch-Site", testCase.fetchSite)
+				}
+
+				res, err := server.Client().Do(req)
+				assert.Nil(t, err)
+				res.Body.Close()
+				assert.Equal(t, testCase.code, res.StatusCode, testCase.method+" "+testCase.path)
+			}
+
+			info, err := schedule.Get("cross#1")
+			assert.Nil(t, err)
+			assert.Equal(t, scheduler.JobStatusPaused, info.Status)
+		})
+	})
+}
@@ -0,0 +1,367 @@
+package adminhandler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxClockSkew = 5 * time.Minute
+
+	HeaderKeyID     = "X-Scheduler-Key-Id"
+	HeaderTimestamp = "X-Scheduler-Timestamp"
+	HeaderNonce     = "X-Scheduler-Nonce"
+	HeaderSignature = "X-Scheduler-Signature"
+)
+
+var (
+	ErrUnauthenticated = errors.New("adminhandler: the request is not authenticated")
+	ErrForbidden       = errors.New("adminhandler: the operation is forbidden")
+
+	// errNoCredentials lets the next authenticator try a request without
+	// the credentials of an authenticator.
+	errNoCredentials = errors.New("adminhandler: no credentials")
+)
+
+type Operation string
+
+const (
+	OperationList       Operation = "list"
+	OperationGet        Operation = "get"
+	OperationCreate     Operation = "create"
+	OperationCancel     Operation = "cancel"
+	OperationReschedule Operation = "reschedule"
+	OperationPause      Operation = "pause"
+	OperationResume     Operation = "resume"
+	OperationRun        Operation = "run"
+	OperationHistory    Operation = "history"
+	OperationWatch      Operation = "watch"
+)
+
+// isCollection reports whether op reads several jobs, which are filtered by
+// the keys the principal may read.
+func (op Operation) isCollection() bool {
+	return op == OperationList || op == OperationHistory || op == OperationWatch
+}
+
+// isRead reports whether op leaves the jobs as they are.
+func (op Operation) isRead() bool {
+	return op == OperationGet || op.isCollection()
+}
+
+type Principal struct {
+	Name  string
+	Roles []string
+}
+
+type principalKey struct{}
+
+func PrincipalFromContext(ctx context.Context) (principal *Principal, isExists bool) {
+	principal, isExists = ctx.Value(principalKey{}).(*Principal)
+	return
+}
+
+// Authenticator returns the principal of a request. A request without the
+// credentials it expects is left to the next authenticator of Config.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Rule allows Operations on the keys starting with one of KeyPrefixes, an
+// empty KeyPrefixes allows every key.
+type Rule struct {
+	Operations  []Operation
+	KeyPrefixes []string
+}
+
+type Role struct {
+	Name  string
+	Rules []Rule
+}
+
+// AuditRecord describes a mutation made through the handler.
+type AuditRecord struct {
+	Time      time.Time
+	Principal *Principal
+	Operation Operation
+	Key       string
+	Err       error
+}
+
+func (rule *Rule) allowsOperation(op Operation) bool {
+	for _, operation := range rule.Operations {
+		if operation == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule *Rule) allowsKey(key string) bool {
+	if len(rule.KeyPrefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range rule.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) authenticate(r *http.Request) (principal *Principal, err error) {
+	if len(h.config.Authenticators) == 0 {
+		return &Principal{Name: "anonymous"}, nil
+	}
+
+	for _, authenticator := range h.config.Authenticators {
+		principal, err = authenticator.Authenticate(r)
+		if errors.Is(err, errNoCredentials) {
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Join(ErrUnauthenticated, err)
+		}
+		return
+	}
+	return nil, ErrUnauthenticated
+}
+
+// authorize checks op on key, every operation is allowed without
+// authenticators. A collection operation without a key only checks the
+// operation, the jobs it reads are filtered by key afterwards.
+func (h *Handler) authorize(principal *Principal, op Operation, key string) bool {
+	if len(h.config.Authenticators) == 0 {
+		return true
+	}
+
+	for _, name := range principal.Roles {
+		role, isExists := h.roles[name]
+		if !isExists {
+			continue
+		}
+
+		for i := range role.Rules {
+			rule := &role.Rules[i]
+			if rule.allowsOperation(op) && (op.isCollection() && key == "" || rule.allowsKey(key)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (h *Handler) audit(r *http.Request, op Operation, key string, err error) {
+	if h.config.Audit == nil {
+		return
+	}
+
+	principal, _ := PrincipalFromContext(r.Context())
+	h.config.Audit(AuditRecord{
+		Time:      time.Now(),
+		Principal: principal,
+		Operation: op,
+		Key:       key,
+		Err:       err,
+	})
+}
+
+type tokenAuthenticator struct {
+	tokens map[string]*Principal
+}
+
+// NewTokenAuthenticator authenticates the requests having a bearer token of
+// tokens in their Authorization header.
+func NewTokenAuthenticator(tokens map[string]*Principal) Authenticator {
+	return &tokenAuthenticator{tokens: tokens}
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, isFound := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !isFound {
+		return nil, errNoCredentials
+	}
+
+	for t, principal := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return principal, nil
+		}
+	}
+	return nil, errors.New("unknown token")
+}
+
+// HMACKey is a shared secret of a principal.
+type HMACKey struct {
+	Secret    []byte
+	Principal *Principal
+}
+
+type hmacAuthenticator struct {
+	keys         map[string]*HMACKey
+	maxClockSkew time.Duration
+	maxBodyBytes int64
+
+	mutex sync.Mutex
+	// nonces holds the expiry of the nonces seen by key, past it their
+	// timestamp is rejected anyway.
+	nonces    map[string]time.Time
+	lastPurge time.Time
+}
+
+// NewHMACAuthenticator authenticates the requests signed with a key of
+// keys, see SignRequest. Requests whose timestamp is more than maxClockSkew
+// away from now are rejected, default to 5 minutes, and so are the nonces
+// already seen within it.
+func NewHMACAuthenticator(keys map[string]*HMACKey, maxClockSkew time.Duration) Authenticator {
+	if maxClockSkew <= 0 {
+		maxClockSkew = defaultMaxClockSkew
+	}
+
+	return &hmacAuthenticator{
+		keys:         keys,
+		maxClockSkew: maxClockSkew,
+		maxBodyBytes: defaultMaxBodyBytes,
+		nonces:       make(map[string]time.Time),
+	}
+}
+
+// signature is the hex HMAC-SHA256 of the method, the escaped path with its
+// query, the timestamp, the nonce and the hex SHA-256 of the body, separated
+// by new lines. The path is the one sent by the client, so the handler may
+// be mounted under a prefix with http.StripPrefix.
+func signature(secret []byte, r *http.Request, timestamp, nonce string, body []byte) string {
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+
+	sum := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	_, _ = io.WriteString(mac, r.Method+"\n"+uri+"\n"+timestamp+"\n"+nonce+"\n"+hex.EncodeToString(sum[:]))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest signs r for a HMAC authenticator with the key named keyID and
+// a random nonce, a signed request is only accepted once.
+func SignRequest(r *http.Request, keyID string, secret []byte) (err error) {
+	var body []byte
+	if r.Body != nil {
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	random := make([]byte, 16)
+	if _, err = rand.Read(random); err != nil {
+		return
+	}
+
+	timestamp, nonce := strconv.FormatInt(time.Now().Unix(), 10), hex.EncodeToString(random)
+	r.Header.Set(HeaderKeyID, keyID)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, signature(secret, r, timestamp, nonce, body))
+	return
+}
+
+func (a *hmacAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	keyID := r.Header.Get(HeaderKeyID)
+	if keyID == "" {
+		return nil, errNoCredentials
+	}
+
+	key, isExists := a.keys[keyID]
+	if !isExists {
+		return nil, errors.New("unknown key " + keyID)
+	}
+
+	timestamp := r.Header.Get(HeaderTimestamp)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid timestamp")
+	}
+
+	if skew := time.Since(time.Unix(unix, 0)); skew > a.maxClockSkew || skew < -a.maxClockSkew {
+		return nil, errors.New("expired timestamp")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, a.maxBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	nonce := r.Header.Get(HeaderNonce)
+	if nonce == "" {
+		return nil, errors.New("missing nonce")
+	}
+
+	expected := signature(key.Secret, r, timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get(HeaderSignature))) {
+		return nil, errors.New("invalid signature")
+	}
+
+	if !a.useNonce(keyID+"\n"+nonce, time.Unix(unix, 0).Add(a.maxClockSkew)) {
+		return nil, errors.New("replayed nonce")
+	}
+	return key.Principal, nil
+}
+
+// useNonce records nonce until expiry and reports whether it was unseen.
+// The expired nonces are purged at most once per maxClockSkew.
+func (a *hmacAuthenticator) useNonce(nonce string, expiry time.Time) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(a.lastPurge) > a.maxClockSkew {
+		for seen, seenExpiry := range a.nonces {
+			if now.After(seenExpiry) {
+				delete(a.nonces, seen)
+			}
+		}
+		a.lastPurge = now
+	}
+
+	if seenExpiry, isExists := a.nonces[nonce]; isExists && !now.After(seenExpiry) {
+		return false
+	}
+
+	a.nonces[nonce] = expiry
+	return true
+}
+
+type certAuthenticator struct {
+	roles map[string][]string
+}
+
+// NewCertAuthenticator authenticates the requests made with a verified TLS
+// client certificate, the principal is named by the common name of the
+// certificate and has the roles mapped to it by roles.
+func NewCertAuthenticator(roles map[string][]string) Authenticator {
+	return &certAuthenticator{roles: roles}
+}
+
+func (a *certAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, errNoCredentials
+	}
+
+	name := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	return &Principal{Name: name, Roles: a.roles[name]}, nil
+}
@@ -0,0 +1,268 @@
+package adminhandler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+var roles = []Role{
+	{Name: "viewer", Rules: []Rule{{Operations: []Operation{OperationList, OperationGet}}}},
+	{Name: "billing", Rules: []Rule{{
+		Operations:  []Operation{OperationList, OperationGet, OperationCreate, OperationCancel, OperationRun},
+		KeyPrefixes: []string{"billing/"},
+	}}},
+	{Name: "watcher", Rules: []Rule{{Operations: []Operation{OperationWatch}, KeyPrefixes: []string{"billing/"}}}},
+}
+
+type auditLog struct {
+	mutex   sync.Mutex
+	records []AuditRecord
+}
+
+func (l *auditLog) audit(record AuditRecord) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.records = append(l.records, record)
+}
+
+func newAuthServer(t *testing.T, authenticators ...Authenticator) (*scheduler.Scheduler, *auditLog, *httptest.Server) {
+	schedule := scheduler.NewScheduler()
+	assert.Nil(t, schedule.RegisterHandler("handler", fn))
+	log := &auditLog{}
+	server := httptest.NewServer(NewHandler(schedule, Config{
+		Authenticators: authenticators,
+		Roles:          roles,
+		Audit:          log.audit,
+	}))
+	t.Cleanup(server.Close)
+	return schedule, log, server
+}
+
+func doAs(t *testing.T, server *httptest.Server, token, method, path, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	assert.Nil(t, err)
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := server.Client().Do(req)
+	assert.Nil(t, err)
+	res.Body.Close()
+	return res
+}
+
+func TestAuth(t *testing.T) {
+	tokens := NewTokenAuthenticator(map[string]*Principal{
+		"viewer-token":  {Name: "alice", Roles: []string{"viewer"}},
+		"billing-token": {Name: "bob", Roles: []string{"billing"}},
+	})
+
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Bearer token scoped to key prefix", func(t *testing.T) {
+			t.Parallel()
+			schedule, log, server := newAuthServer(t, tokens)
+
+			res := doAs(t, server, "billing-token", http.MethodPost, "/jobs", `{"key":"billing/1","handler":"handler","delay":"1h"}`)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			res = doAs(t, server, "billing-token", http.MethodPost, "/jobs/billing%2F1/run", "")
+			assert.Equal(t, http.StatusAccepted, res.StatusCode)
+			res = doAs(t, server, "billing-token", http.MethodDelete, "/jobs/billing%2F1", "")
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			_, err := schedule.Get("billing/1")
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, err)
+
+			assert.Len(t, log.records, 3)
+			for i, op := range []Operation{OperationCreate, OperationRun, OperationCancel} {
+				assert.Equal(t, op, log.records[i].Operation)
+				assert.Equal(t, "bob", log.records[i].Principal.Name)
+				assert.Equal(t, "billing/1", log.records[i].Key)
+				assert.Nil(t, log.records[i].Err)
+			}
+		})
+
+		t.Run("List only the allowed keys", func(t *testing.T) {
+			t.Parallel()
+			schedule, _, server := newAuthServer(t, tokens)
+			assert.Nil(t, schedule.Add("billing/1", time.Hour, fn))
+			assert.Nil(t, schedule.Add("report/1", time.Hour, fn))
+
+			for token, keys := range map[string][]string{
+				"billing-token": {"billing/1"},
+				"viewer-token":  {"billing/1", "report/1"},
+			} {
+				req, err := http.NewRequest(http.MethodGet, server.URL+"/jobs?sort=key", nil)
+				assert.Nil(t, err)
+				req.Header.Set("Authorization", "Bearer "+token)
+				res, err := server.Client().Do(req)
+				assert.Nil(t, err)
+
+				var data []map[string]interface{}
+				assert.Nil(t, json.NewDecoder(res.Body).Decode(&data))
+				res.Body.Close()
+
+				var listed []string
+				for _, job := range data {
+					listed = append(listed, job["key"].(string))
+				}
+				assert.Equal(t, keys, listed, token)
+			}
+		})
+
+		t.Run("HMAC signed request", func(t *testing.T) {
+			t.Parallel()
+			secret := []byte("secret")
+			_, log, server := newAuthServer(t, tokens, NewHMACAuthenticator(map[string]*HMACKey{
+				"ci": {Secret: secret, Principal: &Principal{Name: "ci", Roles: []string{"billing"}}},
+			}, 0))
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/jobs", strings.NewReader(`{"key":"billing/2","handler":"handler","delay":"1h"}`))
+			assert.Nil(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			assert.Nil(t, SignRequest(req, "ci", secret))
+			res, err := server.Client().Do(req)
+			assert.Nil(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Equal(t, "ci", log.records[0].Principal.Name)
+		})
+
+		t.Run("Client certificate", func(t *testing.T) {
+			t.Parallel()
+			schedule := scheduler.NewScheduler()
+			assert.Nil(t, schedule.Add("billing/1", time.Hour, fn))
+			handler := NewHandler(schedule, Config{
+				Authenticators: []Authenticator{NewCertAuthenticator(map[string][]string{"ops": {"viewer"}})},
+				Roles:          roles,
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/billing%2F1", nil)
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+				{Subject: pkix.Name{CommonName: "ops"}},
+			}}}
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+			assert.Equal(t, http.StatusOK, res.Code)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Missing or unknown credentials", func(t *testing.T) {
+			t.Parallel()
+			_, _, server := newAuthServer(t, tokens)
+
+			res := doAs(t, server, "", http.MethodGet, "/jobs", "")
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+			assert.NotEmpty(t, res.Header.Get("WWW-Authenticate"))
+			res = doAs(t, server, "unknown", http.MethodGet, "/jobs", "")
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		})
+
+		t.Run("Operation or key not allowed", func(t *testing.T) {
+			t.Parallel()
+			schedule, log, server := newAuthServer(t, tokens)
+			assert.Nil(t, schedule.Add("report/1", time.Hour, fn))
+
+			res := doAs(t, server, "viewer-token", http.MethodDelete, "/jobs/report%2F1", "")
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			res = doAs(t, server, "billing-token", http.MethodGet, "/jobs/report%2F1", "")
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			res = doAs(t, server, "billing-token", http.MethodPost, "/jobs", `{"key":"report/2","handler":"handler","delay":"1h"}`)
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			res = doAs(t, server, "billing-token", http.MethodPost, "/jobs/report%2F1/pause", "")
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assert.Empty(t, log.records)
+		})
+
+		t.Run("Tampered or expired signature", func(t *testing.T) {
+			t.Parallel()
+			secret := []byte("secret")
+			_, _, server := newAuthServer(t, NewHMACAuthenticator(map[string]*HMACKey{
+				"ci": {Secret: secret, Principal: &Principal{Name: "ci", Roles: []string{"billing"}}},
+			}, time.Minute))
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/jobs", strings.NewReader(`{"key":"billing/2","handler":"handler","delay":"1h"}`))
+			assert.Nil(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			assert.Nil(t, SignRequest(req, "ci", secret))
+			req.Body = http.NoBody
+			req.ContentLength = 0
+			res, err := server.Client().Do(req)
+			assert.Nil(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+			req, err = http.NewRequest(http.MethodGet, server.URL+"/jobs", nil)
+			assert.Nil(t, err)
+			assert.Nil(t, SignRequest(req, "ci", secret))
+			req.Header.Set(HeaderTimestamp, "1")
+			res, err = server.Client().Do(req)
+			assert.Nil(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+			req, err = http.NewRequest(http.MethodGet, server.URL+"/jobs", nil)
+			assert.Nil(t, err)
+			assert.Nil(t, SignRequest(req, "ci", secret))
+			req.Header.Set(HeaderNonce, "other")
+			res, err = server.Client().Do(req)
+			assert.Nil(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		})
+
+		t.Run("Replayed signature", func(t *testing.T) {
+			t.Parallel()
+			secret := []byte("secret")
+			schedule, _, server := newAuthServer(t, NewHMACAuthenticator(map[string]*HMACKey{
+				"ci": {Secret: secret, Principal: &Principal{Name: "ci", Roles: []string{"billing"}}},
+			}, time.Minute))
+			assert.Nil(t, schedule.Add("billing/1", time.Hour, fn))
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/jobs/billing%2F1/run", nil)
+			assert.Nil(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			assert.Nil(t, SignRequest(req, "ci", secret))
+			for _, code := range []int{http.StatusAccepted, http.StatusUnauthorized} {
+				res, err := server.Client().Do(req.Clone(req.Context()))
+				assert.Nil(t, err)
+				res.Body.Close()
+				assert.Equal(t, code, res.StatusCode)
+			}
+
+			assert.Nil(t, SignRequest(req, "ci", secret))
+			res, err := server.Client().Do(req)
+			assert.Nil(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusAccepted, res.StatusCode)
+		})
+
+		t.Run("Client certificate without verified chain", func(t *testing.T) {
+			t.Parallel()
+			handler := NewHandler(scheduler.NewScheduler(), Config{
+				Authenticators: []Authenticator{NewCertAuthenticator(nil)},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+			req.TLS = &tls.ConnectionState{}
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+		})
+	})
+}
@@ -0,0 +1,170 @@
+package adminhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+)
+
+const (
+	defaultKeepAliveInterval = 15 * time.Second
+)
+
+var eventTypes = map[string]scheduler.EventType{}
+
+func init() {
+	for t := scheduler.EventTypeScheduled; t <= scheduler.EventTypeRemoved; t++ {
+		eventTypes[t.String()] = t
+	}
+}
+
+// History is the JSON representation of the retained runs of a key, oldest
+// first.
+type History struct {
+	Key  string `json:"key"`
+	Runs []*Run `json:"runs"`
+}
+
+// Event is the JSON representation of an event, sent as the data of a
+// server-sent event named by Type.
+type Event struct {
+	Type             string     `json:"type"`
+	Key              string     `json:"key"`
+	Handler          string     `json:"handler,omitempty"`
+	DateTime         time.Time  `json:"date_time"`
+	PreviousDateTime *time.Time `json:"previous_date_time,omitempty"`
+	StartTime        *time.Time `json:"start_time,omitempty"`
+	EndTime          *time.Time `json:"end_time,omitempty"`
+	Attempt          int        `json:"attempt,omitempty"`
+	Error            string     `json:"error,omitempty"`
+	Output           string     `json:"output,omitempty"`
+}
+
+func toEvent(event scheduler.Event) *Event {
+	res := &Event{
+		Type:             event.Type.String(),
+		Key:              event.Key,
+		Handler:          event.Handler,
+		DateTime:         event.DateTime,
+		PreviousDateTime: optionalTime(event.PreviousDateTime),
+		StartTime:        optionalTime(event.StartTime),
+		EndTime:          optionalTime(event.EndTime),
+		Attempt:          event.Attempt,
+		Output:           event.Output,
+	}
+
+	if event.Err != nil {
+		res.Error = event.Err.Error()
+	}
+	return res
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// history writes the retained runs of the keys starting with the prefix
+// parameter.
+func (h *Handler) history(w http.ResponseWriter, r *http.Request) {
+	var (
+		prefix       = r.URL.Query().Get("prefix")
+		principal, _ = PrincipalFromContext(r.Context())
+		res          = make([]*History, 0)
+	)
+
+	for _, key := range h.scheduler.HistoryKeys() {
+		if !strings.HasPrefix(key, prefix) || !h.authorize(principal, OperationHistory, key) {
+			continue
+		}
+
+		runs := h.scheduler.History(key)
+		if len(runs) == 0 {
+			continue
+		}
+
+		history := &History{Key: key, Runs: make([]*Run, 0, len(runs))}
+		for _, run := range runs {
+			history.Runs = append(history.Runs, toRun(run))
+		}
+		res = append(res, history)
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func toWatchFilter(r *http.Request) (filter scheduler.WatchFilter, err error) {
+	values := r.URL.Query()
+	filter.KeyPrefix = values.Get("prefix")
+	for _, name := range values["type"] {
+		eventType, isExists := eventTypes[name]
+		if !isExists {
+			err = errors.Join(ErrRequestBody, errors.New("unknown type "+name))
+			return
+		}
+		filter.Types = append(filter.Types, eventType)
+	}
+	return
+}
+
+// watch streams the events matching the prefix and type parameters until the
+// client goes away. The stream ends when the client is too slow to keep up,
+// an EventSource then reconnects by itself.
+func (h *Handler) watch(w http.ResponseWriter, r *http.Request) {
+	filter, err := toWatchFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	flusher, isFlusher := w.(http.Flusher)
+	if !isFlusher {
+		writeError(w, errors.New("adminhandler: streaming is not supported"))
+		return
+	}
+
+	principal, _ := PrincipalFromContext(r.Context())
+	events := h.scheduler.Watch(r.Context(), filter)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(h.config.KeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, isOpen := <-events:
+			if !isOpen {
+				return
+			}
+
+			if !h.authorize(principal, OperationWatch, event.Key) {
+				continue
+			}
+
+			data, err := json.Marshal(toEvent(event))
+			if err != nil {
+				return
+			}
+
+			if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,131 @@
+package adminhandler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func readEvent(t *testing.T, reader *bufio.Reader) (name string, event *Event) {
+	t.Helper()
+
+	for {
+		line, err := reader.ReadString('\n')
+		assert.Nil(t, err)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: "):
+			event = &Event{}
+			assert.Nil(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), event))
+		case line == "\n" && event != nil:
+			return
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("History with errors", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			assert.Nil(t, schedule.Add("add#1", time.Millisecond, func(ctx context.Context) {
+				scheduler.SetOutput(ctx, "output")
+				scheduler.Fail(ctx, errors.New("failure"))
+			}))
+			assert.Nil(t, schedule.Add("other#1", time.Millisecond, fn))
+			assert.Eventually(t, func() bool {
+				return len(schedule.HistoryKeys()) == 2
+			}, time.Second, 5*time.Millisecond)
+
+			res, err := server.Client().Get(server.URL + "/history?prefix=add")
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			var histories []*History
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&histories))
+			assert.Len(t, histories, 1)
+			assert.Equal(t, "add#1", histories[0].Key)
+			assert.Equal(t, "failure", histories[0].Runs[0].Outcome)
+			assert.Equal(t, "failure", histories[0].Runs[0].Error)
+			assert.Equal(t, "output", histories[0].Runs[0].Output)
+		})
+
+		t.Run("Stream events", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events?prefix=add&type=scheduled&type=cancelled", nil)
+			assert.Nil(t, err)
+			res, err := server.Client().Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+			assert.Nil(t, schedule.Add("other#1", time.Hour, fn))
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+			assert.Nil(t, schedule.Cancel("add#1"))
+
+			reader := bufio.NewReader(res.Body)
+			name, event := readEvent(t, reader)
+			assert.Equal(t, "scheduled", name)
+			assert.Equal(t, "add#1", event.Key)
+			name, event = readEvent(t, reader)
+			assert.Equal(t, "cancelled", name)
+			assert.Equal(t, "cancelled", event.Type)
+		})
+
+		t.Run("Stream only the allowed keys", func(t *testing.T) {
+			t.Parallel()
+			schedule, _, server := newAuthServer(t, NewTokenAuthenticator(map[string]*Principal{
+				"token": {Name: "bob", Roles: []string{"watcher"}},
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+			assert.Nil(t, err)
+			req.Header.Set("Authorization", "Bearer token")
+			res, err := server.Client().Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Nil(t, schedule.Add("report/1", time.Hour, fn))
+			assert.Nil(t, schedule.Add("billing/1", time.Hour, fn))
+			_, event := readEvent(t, bufio.NewReader(res.Body))
+			assert.Equal(t, "billing/1", event.Key)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Unknown event type or method", func(t *testing.T) {
+			t.Parallel()
+			_, server := newServer(t)
+
+			res, data := do(t, server, http.MethodGet, "/events?type=unknown", "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			assert.Contains(t, data["error"], "unknown type")
+
+			res, _ = do(t, server, http.MethodPost, "/history", "")
+			assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+			assert.Equal(t, http.MethodGet, res.Header.Get("Allow"))
+
+			res, _ = do(t, server, http.MethodGet, "/history/add", "")
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+	})
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/sodri126/go-simple-scheduler/adminhandler"
+)
+
+const (
+	socketBaseURL = "http://unix"
+)
+
+type clientConfig struct {
+	addr     string
+	socket   string
+	token    string
+	keyID    string
+	secret   string
+	certFile string
+	keyFile  string
+	caFile   string
+	insecure bool
+}
+
+type client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+	keyID   string
+	secret  []byte
+}
+
+// apiError is an error answered by the admin API.
+type apiError struct {
+	code    int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.message, e.code)
+}
+
+func newClient(config *clientConfig) (c *client, err error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	c = &client{
+		http:    &http.Client{Transport: transport},
+		baseURL: strings.TrimSuffix(config.addr, "/"),
+		token:   config.token,
+		keyID:   config.keyID,
+		secret:  []byte(config.secret),
+	}
+
+	if config.socket != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", config.socket)
+		}
+		c.baseURL = socketBaseURL
+		return
+	}
+
+	if !strings.HasPrefix(c.baseURL, "https://") {
+		return
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.insecure}
+	if config.caFile != "" {
+		var pem []byte
+		if pem, err = os.ReadFile(config.caFile); err != nil {
+			return
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			err = errors.New("no certificate found in " + config.caFile)
+			return
+		}
+	}
+
+	if config.certFile != "" {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(config.certFile, config.keyFile); err != nil {
+			return
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return
+}
+
+// do sends a request to the admin API, an answer other than 2xx is returned
+// as an apiError.
+func (c *client) do(ctx context.Context, method, path string, body interface{}) (res *http.Response, err error) {
+	var reader io.Reader
+	if body != nil {
+		var data []byte
+		if data, err = json.Marshal(body); err != nil {
+			return
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return
+	}
+
+	// The admin API only serves the mutations sent as application/json,
+	// even those without a body.
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	if c.keyID != "" {
+		if err = adminhandler.SignRequest(req, c.keyID, c.secret); err != nil {
+			return
+		}
+	}
+
+	if res, err = c.http.Do(req); err != nil {
+		return
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		e := &apiError{code: res.StatusCode, message: http.StatusText(res.StatusCode)}
+		var data struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(res.Body).Decode(&data) == nil && data.Error != "" {
+			e.message = data.Error
+		}
+		return nil, e
+	}
+	return
+}
+
+// decode sends a request and decodes the JSON answer into v.
+func (c *client) decode(ctx context.Context, method, path string, body, v interface{}) (res *http.Response, err error) {
+	if res, err = c.do(ctx, method, path, body); err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if v != nil {
+		err = json.NewDecoder(res.Body).Decode(v)
+	}
+	return
+}
+
+func jobPath(key string, actions ...string) string {
+	return "/jobs/" + strings.Join(append([]string{url.PathEscape(key)}, actions...), "/")
+}
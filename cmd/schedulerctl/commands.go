@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/sodri126/go-simple-scheduler/adminhandler"
+)
+
+const (
+	outputTable  = "table"
+	outputJSON   = "json"
+	outputDetail = "detail"
+)
+
+var errUsage = errors.New("usage")
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, cmd *commandContext, args []string) error
+}
+
+type commandContext struct {
+	client *client
+	output string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+var commands = []*command{
+	{"list", "[-prefix p] [-glob g] [-tag t]... [-status s]... [-sort date_time|key] [-limit n] [-cursor c]", "list the jobs", runList},
+	{"get", "KEY", "show a job", runGet},
+	{"cancel", "KEY", "cancel a job", runAction("")},
+	{"reschedule", "KEY (-at RFC3339 | -in DURATION)", "move a job to another date time", runReschedule},
+	{"pause", "KEY", "pause a job", runAction("pause")},
+	{"resume", "KEY", "resume a paused job", runAction("resume")},
+	{"run", "KEY", "run a job now without changing its schedule", runAction("run")},
+	{"watch", "[-prefix p] [-type t]...", "stream the events until interrupted", runWatch},
+}
+
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// keyArg returns the only positional argument of a command.
+func keyArg(args []string) (string, error) {
+	if len(args) != 1 || args[0] == "" {
+		return "", errUsage
+	}
+	return args[0], nil
+}
+
+func toResponseScheduler(job *adminhandler.Job) (*scheduler.ResponseScheduler, error) {
+	dateTime, err := time.Parse(time.RFC3339Nano, job.DateTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scheduler.ResponseScheduler{
+		Key:     job.Key,
+		Time:    dateTime,
+		Handler: job.Handler,
+		Status:  scheduler.JobStatus(job.Status),
+		Tags:    job.Tags,
+	}, nil
+}
+
+// render writes jobs with the table or the JSON converter of the scheduler,
+// or as returned by the admin API with the detail output.
+func (cmd *commandContext) render(jobs []*adminhandler.Job) (err error) {
+	if cmd.output == outputDetail {
+		encoder := json.NewEncoder(cmd.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(jobs)
+	}
+
+	data := make([]*scheduler.ResponseScheduler, 0, len(jobs))
+	for _, job := range jobs {
+		var res *scheduler.ResponseScheduler
+		if res, err = toResponseScheduler(job); err != nil {
+			return
+		}
+		data = append(data, res)
+	}
+
+	lc := scheduler.NewDefaultResponse()
+	if cmd.output == outputJSON {
+		lc = scheduler.NewJsonResponse()
+	}
+
+	bytes, err := lc.Convert(data)
+	if err != nil {
+		return
+	}
+
+	if _, err = cmd.stdout.Write(bytes); err == nil && !strings.HasSuffix(string(bytes), "\n") {
+		_, err = io.WriteString(cmd.stdout, "\n")
+	}
+	return
+}
+
+func runList(ctx context.Context, cmd *commandContext, args []string) (err error) {
+	var (
+		flags    = newFlagSet("list", cmd.stderr)
+		prefix   = flags.String("prefix", "", "only the keys starting with `prefix`")
+		glob     = flags.String("glob", "", "only the keys matching the `pattern`")
+		sort     = flags.String("sort", "date_time", "sort by date_time or key")
+		limit    = flags.Int("limit", 0, "return at most `n` jobs, 0 returns every job")
+		cursor   = flags.String("cursor", "", "continue after the page of `cursor`")
+		tags     stringsFlag
+		statuses stringsFlag
+	)
+	flags.Var(&tags, "tag", "only the jobs carrying the `tag`, repeatable")
+	flags.Var(&statuses, "status", "only the jobs with the `status`, repeatable")
+	if err = flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	if flags.NArg() > 0 {
+		return errUsage
+	}
+
+	values := url.Values{"format": {"detail"}, "sort": {*sort}}
+	for name, value := range map[string]string{"prefix": *prefix, "glob": *glob, "cursor": *cursor} {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	if *limit > 0 {
+		values.Set("limit", strconv.Itoa(*limit))
+	}
+	values["tag"] = tags
+	values["status"] = statuses
+
+	var jobs []*adminhandler.Job
+	res, err := cmd.client.decode(ctx, http.MethodGet, "/jobs?"+values.Encode(), nil, &jobs)
+	if err != nil {
+		return
+	}
+
+	if err = cmd.render(jobs); err != nil {
+		return
+	}
+
+	if next := res.Header.Get("X-Next-Cursor"); next != "" {
+		fmt.Fprintf(cmd.stderr, "next page: -cursor %s\n", next)
+	}
+	return
+}
+
+func runGet(ctx context.Context, cmd *commandContext, args []string) (err error) {
+	key, err := keyArg(args)
+	if err != nil {
+		return
+	}
+
+	job := &adminhandler.Job{}
+	if _, err = cmd.client.decode(ctx, http.MethodGet, jobPath(key), nil, job); err != nil {
+		return
+	}
+	return cmd.render([]*adminhandler.Job{job})
+}
+
+// runAction returns a command posting action on a job, an empty action
+// cancels the job.
+func runAction(action string) func(ctx context.Context, cmd *commandContext, args []string) error {
+	return func(ctx context.Context, cmd *commandContext, args []string) (err error) {
+		key, err := keyArg(args)
+		if err != nil {
+			return
+		}
+
+		if action == "" {
+			_, err = cmd.client.decode(ctx, http.MethodDelete, jobPath(key), nil, nil)
+			return
+		}
+
+		job := &adminhandler.Job{}
+		if _, err = cmd.client.decode(ctx, http.MethodPost, jobPath(key, action), nil, job); err != nil {
+			return
+		}
+		return cmd.render([]*adminhandler.Job{job})
+	}
+}
+
+func runReschedule(ctx context.Context, cmd *commandContext, args []string) (err error) {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	var (
+		key   = args[0]
+		flags = newFlagSet("reschedule", cmd.stderr)
+		at    = flags.String("at", "", "the new date time, RFC 3339")
+		in    = flags.Duration("in", 0, "the new delay from now")
+	)
+	if err = flags.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	req := &adminhandler.RescheduleRequest{DateTime: *at}
+	switch {
+	case flags.NArg() > 0, *at != "" && *in != 0, *at == "" && *in <= 0:
+		return errUsage
+	case *in > 0:
+		req.Delay = in.String()
+	}
+
+	job := &adminhandler.Job{}
+	if _, err = cmd.client.decode(ctx, http.MethodPost, jobPath(key, "reschedule"), req, job); err != nil {
+		return
+	}
+	return cmd.render([]*adminhandler.Job{job})
+}
+
+// runWatch prints every event on its own line, as the JSON sent by the admin
+// API with the json and detail outputs.
+func runWatch(ctx context.Context, cmd *commandContext, args []string) (err error) {
+	var (
+		flags  = newFlagSet("watch", cmd.stderr)
+		prefix = flags.String("prefix", "", "only the keys starting with `prefix`")
+		types  stringsFlag
+	)
+	flags.Var(&types, "type", "only the events of `type`, repeatable")
+	if err = flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return errUsage
+	}
+
+	values := url.Values{"type": types}
+	if *prefix != "" {
+		values.Set("prefix", *prefix)
+	}
+
+	res, err := cmd.client.do(ctx, http.MethodGet, "/events?"+values.Encode(), nil)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		data, isData := strings.CutPrefix(scanner.Text(), "data: ")
+		if !isData {
+			continue
+		}
+
+		if cmd.output != outputTable {
+			fmt.Fprintln(cmd.stdout, data)
+			continue
+		}
+
+		event := &adminhandler.Event{}
+		if err = json.Unmarshal([]byte(data), event); err != nil {
+			return
+		}
+
+		line := fmt.Sprintf("%s  %-11s  %s  %s", time.Now().Format(time.RFC3339), event.Type, event.Key, event.DateTime.Format(time.RFC3339))
+		if event.Error != "" {
+			line += "  " + event.Error
+		}
+		fmt.Fprintln(cmd.stdout, line)
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err = scanner.Err(); err == nil {
+		err = errors.New("the event stream is closed")
+	}
+	return
+}
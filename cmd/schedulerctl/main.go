@@ -0,0 +1,100 @@
+// Command schedulerctl inspects and manages a scheduler through the admin
+// API served by adminhandler, over HTTP or a Unix socket.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const (
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func usage(w io.Writer, global func()) {
+	fmt.Fprintln(w, "usage: schedulerctl [flags] COMMAND [ARGS]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nflags:")
+	global()
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	var (
+		config = &clientConfig{}
+		flags  = newFlagSet("schedulerctl", stderr)
+		output = flags.String("o", outputTable, "output `format`: table, json or detail")
+	)
+	flags.StringVar(&config.addr, "addr", envOr("SCHEDULERCTL_ADDR", "http://localhost:8080"), "`URL` of the admin API")
+	flags.StringVar(&config.socket, "socket", os.Getenv("SCHEDULERCTL_SOCKET"), "`path` of a Unix socket serving the admin API, overrides -addr")
+	flags.StringVar(&config.token, "token", os.Getenv("SCHEDULERCTL_TOKEN"), "bearer `token`")
+	flags.StringVar(&config.keyID, "key-id", os.Getenv("SCHEDULERCTL_KEY_ID"), "`name` of the key signing the requests")
+	flags.StringVar(&config.secret, "secret", os.Getenv("SCHEDULERCTL_SECRET"), "`secret` signing the requests with -key-id")
+	flags.StringVar(&config.certFile, "cert", "", "client certificate `file`")
+	flags.StringVar(&config.keyFile, "key", "", "client private key `file`")
+	flags.StringVar(&config.caFile, "ca", "", "CA certificates `file` verifying the server")
+	flags.BoolVar(&config.insecure, "insecure", false, "skip the verification of the server certificate")
+	flags.Usage = func() {
+		usage(stderr, flags.PrintDefaults)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() == 0 || (*output != outputTable && *output != outputJSON && *output != outputDetail) {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var cmd *command
+	for _, c := range commands {
+		if c.name == flags.Arg(0) {
+			cmd = c
+		}
+	}
+
+	if cmd == nil {
+		fmt.Fprintf(stderr, "schedulerctl: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return exitUsage
+	}
+
+	c, err := newClient(config)
+	if err != nil {
+		fmt.Fprintf(stderr, "schedulerctl: %v\n", err)
+		return exitFailure
+	}
+
+	err = cmd.run(ctx, &commandContext{client: c, output: *output, stdout: stdout, stderr: stderr}, flags.Args()[1:])
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "usage: schedulerctl %s %s\n", cmd.name, cmd.usage)
+		return exitUsage
+	case err != nil:
+		fmt.Fprintf(stderr, "schedulerctl %s: %v\n", cmd.name, err)
+		return exitFailure
+	}
+	return 0
+}
+
+func envOr(name, value string) string {
+	if env := os.Getenv(name); env != "" {
+		return env
+	}
+	return value
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/sodri126/go-simple-scheduler/adminhandler"
+	"github.com/stretchr/testify/assert"
+)
+
+var fn = func(ctx context.Context) {}
+
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func newServer(t *testing.T, configs ...adminhandler.Config) (*scheduler.Scheduler, *httptest.Server) {
+	schedule := scheduler.NewScheduler()
+	server := httptest.NewServer(adminhandler.NewHandler(schedule, configs...))
+	t.Cleanup(server.Close)
+	return schedule, server
+}
+
+func runCtl(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	code = run(context.Background(), args, out, errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("List and get", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			for _, key := range []string{"billing/1", "billing/2", "report/1"} {
+				assert.Nil(t, schedule.Add(key, time.Hour, fn))
+			}
+
+			code, stdout, _ := runCtl(t, "-addr", server.URL, "list", "-prefix", "billing/")
+			assert.Equal(t, 0, code)
+			assert.Contains(t, stdout, "billing/1")
+			assert.Contains(t, stdout, "billing/2")
+			assert.NotContains(t, stdout, "report/1")
+
+			code, stdout, stderr := runCtl(t, "-addr", server.URL, "-o", "json", "list", "-sort", "key", "-limit", "1")
+			assert.Equal(t, 0, code)
+			var data []map[string]interface{}
+			assert.Nil(t, json.Unmarshal([]byte(stdout), &data))
+			assert.Len(t, data, 1)
+			assert.Equal(t, "billing/1", data[0]["key"])
+			assert.Contains(t, stderr, "next page: -cursor ")
+
+			code, stdout, _ = runCtl(t, "-addr", server.URL, "-o", "detail", "get", "billing/2")
+			assert.Equal(t, 0, code)
+			var jobs []*adminhandler.Job
+			assert.Nil(t, json.Unmarshal([]byte(stdout), &jobs))
+			assert.Equal(t, "billing/2", jobs[0].Key)
+			assert.Equal(t, "pending", jobs[0].Status)
+		})
+
+		t.Run("Reschedule, pause, resume, run and cancel", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			assert.Nil(t, schedule.Add("add/1", time.Hour, fn))
+
+			code, _, _ := runCtl(t, "-addr", server.URL, "reschedule", "add/1", "-in", "2h")
+			assert.Equal(t, 0, code)
+			info, err := schedule.Get("add/1")
+			assert.Nil(t, err)
+			assert.InDelta(t, 2*time.Hour, info.Remaining, float64(time.Minute))
+
+			at := time.Now().Add(3 * time.Hour).UTC().Truncate(time.Second)
+			code, _, _ = runCtl(t, "-addr", server.URL, "reschedule", "add/1", "-at", at.Format(time.RFC3339))
+			assert.Equal(t, 0, code)
+			info, _ = schedule.Get("add/1")
+			assert.True(t, info.DateTime.Equal(at))
+
+			code, stdout, _ := runCtl(t, "-addr", server.URL, "-o", "detail", "pause", "add/1")
+			assert.Equal(t, 0, code)
+			assert.Contains(t, stdout, `"status": "paused"`)
+			code, _, _ = runCtl(t, "-addr", server.URL, "resume", "add/1")
+			assert.Equal(t, 0, code)
+			code, _, _ = runCtl(t, "-addr", server.URL, "run", "add/1")
+			assert.Equal(t, 0, code)
+			code, _, _ = runCtl(t, "-addr", server.URL, "cancel", "add/1")
+			assert.Equal(t, 0, code)
+			_, err = schedule.Get("add/1")
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, err)
+		})
+
+		t.Run("Sign requests to a prefixed API", func(t *testing.T) {
+			t.Parallel()
+			schedule := scheduler.NewScheduler()
+			assert.Nil(t, schedule.Add("add/1", time.Hour, fn))
+			handler := adminhandler.NewHandler(schedule, adminhandler.Config{
+				Authenticators: []adminhandler.Authenticator{adminhandler.NewHMACAuthenticator(map[string]*adminhandler.HMACKey{
+					"ops": {Secret: []byte("secret"), Principal: &adminhandler.Principal{Name: "ops", Roles: []string{"admin"}}},
+				}, 0)},
+				Roles: []adminhandler.Role{{Name: "admin", Rules: []adminhandler.Rule{{Operations: []adminhandler.Operation{adminhandler.OperationCancel}}}}},
+			})
+			server := httptest.NewServer(http.StripPrefix("/api", handler))
+			t.Cleanup(server.Close)
+
+			code, _, stderr := runCtl(t, "-addr", server.URL+"/api", "-key-id", "ops", "-secret", "secret", "cancel", "add/1")
+			assert.Equal(t, 0, code, stderr)
+			_, err := schedule.Get("add/1")
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, err)
+		})
+
+		t.Run("Unix socket", func(t *testing.T) {
+			if runtime.GOOS == "windows" {
+				t.Skip("unix sockets are not supported on every windows version")
+			}
+
+			t.Parallel()
+			schedule := scheduler.NewScheduler()
+			assert.Nil(t, schedule.Add("add/1", time.Hour, fn))
+
+			socket := filepath.Join(t.TempDir(), "admin.sock")
+			listener, err := net.Listen("unix", socket)
+			assert.Nil(t, err)
+			server := &http.Server{Handler: adminhandler.NewHandler(schedule)}
+			go server.Serve(listener)
+			t.Cleanup(func() { server.Close() })
+
+			code, stdout, _ := runCtl(t, "-socket", socket, "get", "add/1")
+			assert.Equal(t, 0, code)
+			assert.Contains(t, stdout, "add/1")
+		})
+
+		t.Run("Watch events", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			stdout := &syncBuffer{}
+			done := make(chan int)
+			go func() {
+				done <- run(ctx, []string{"-addr", server.URL, "watch", "-prefix", "add/"}, stdout, &bytes.Buffer{})
+			}()
+
+			assert.Eventually(t, func() bool {
+				_ = schedule.Cancel("add/1")
+				_ = schedule.Add("add/1", time.Hour, fn)
+				return strings.Contains(stdout.String(), "scheduled")
+			}, 2*time.Second, 20*time.Millisecond)
+			assert.Contains(t, stdout.String(), "add/1")
+
+			cancel()
+			assert.Equal(t, 0, <-done)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Errors of the admin API", func(t *testing.T) {
+			t.Parallel()
+			_, server := newServer(t)
+
+			code, _, stderr := runCtl(t, "-addr", server.URL, "get", "add/1")
+			assert.Equal(t, exitFailure, code)
+			assert.Contains(t, stderr, "HTTP 404")
+
+			_, server = newServer(t, adminhandler.Config{
+				Authenticators: []adminhandler.Authenticator{adminhandler.NewTokenAuthenticator(nil)},
+			})
+			code, _, stderr = runCtl(t, "-addr", server.URL, "-token", "unknown", "list")
+			assert.Equal(t, exitFailure, code)
+			assert.Contains(t, stderr, "HTTP 401")
+		})
+
+		t.Run("Usage errors", func(t *testing.T) {
+			t.Parallel()
+			for _, args := range [][]string{
+				{},
+				{"unknown"},
+				{"-o", "xml", "list"},
+				{"get"},
+				{"get", "add/1", "add/2"},
+				{"reschedule", "add/1"},
+				{"reschedule", "add/1", "-in", "1h", "-at", "2030-01-01T00:00:00Z"},
+				{"list", "extra"},
+			} {
+				code, _, _ := runCtl(t, args...)
+				assert.Equal(t, exitUsage, code, args)
+			}
+		})
+	})
+}
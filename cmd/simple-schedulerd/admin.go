@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/sodri126/go-simple-scheduler/adminhandler"
+	"github.com/sodri126/go-simple-scheduler/dashboard"
+)
+
+const (
+	envAdminToken = "SIMPLE_SCHEDULERD_ADMIN_TOKEN"
+	adminRole     = "admin"
+)
+
+var adminOperations = []adminhandler.Operation{
+	adminhandler.OperationList,
+	adminhandler.OperationGet,
+	adminhandler.OperationCreate,
+	adminhandler.OperationCancel,
+	adminhandler.OperationReschedule,
+	adminhandler.OperationPause,
+	adminhandler.OperationResume,
+	adminhandler.OperationRun,
+	adminhandler.OperationHistory,
+	adminhandler.OperationWatch,
+}
+
+// adminConfig returns the config of the dashboard served on addr. The
+// bearer token is read from tokenFile or else envToken. Since the admin API
+// runs any job, a token is always required: without one a random token is
+// returned as generated, and only when addr is a loopback address.
+func adminConfig(addr, tokenFile, envToken string) (config dashboard.Config, generated string, err error) {
+	token := envToken
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return config, "", err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	if token == "" {
+		if !isLoopback(addr) {
+			return config, "", fmt.Errorf("-admin %s is not a loopback address, set -admin-token-file or %s", addr, envAdminToken)
+		}
+
+		data := make([]byte, 32)
+		if _, err = rand.Read(data); err != nil {
+			return
+		}
+		token = hex.EncodeToString(data)
+		generated = token
+	}
+
+	config.Admin = adminhandler.Config{
+		Authenticators: []adminhandler.Authenticator{
+			adminhandler.NewTokenAuthenticator(map[string]*adminhandler.Principal{
+				token: {Name: adminRole, Roles: []string{adminRole}},
+			}),
+		},
+		Roles: []adminhandler.Role{{Name: adminRole, Rules: []adminhandler.Rule{{Operations: adminOperations}}}},
+	}
+	return
+}
+
+// isLoopback reports whether addr only listens on the loopback interface,
+// an empty host listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func checkTLSFlags(cert, key string) error {
+	if (cert == "") != (key == "") {
+		return errors.New("-admin-cert and -admin-key are set together")
+	}
+	return nil
+}
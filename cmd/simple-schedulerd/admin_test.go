@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/sodri126/go-simple-scheduler/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminConfig(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Loopback without token", func(t *testing.T) {
+			t.Parallel()
+			tokens := map[string]struct{}{}
+			for _, addr := range []string{"127.0.0.1:8080", "[::1]:8080", "localhost:8080"} {
+				config, token, err := adminConfig(addr, "", "")
+				assert.Nil(t, err, addr)
+				assert.Len(t, token, 64, addr)
+				assert.Len(t, config.Admin.Authenticators, 1, addr)
+				tokens[token] = struct{}{}
+			}
+			assert.Len(t, tokens, 3)
+
+			config, token, err := adminConfig("127.0.0.1:8080", "", "")
+			assert.Nil(t, err)
+			server := httptest.NewServer(dashboard.NewHandler(scheduler.NewScheduler(), config))
+			defer server.Close()
+			for bearer, code := range map[string]int{"": http.StatusUnauthorized, token: http.StatusNotFound} {
+				req, err := http.NewRequest(http.MethodPost, server.URL+"/api/jobs/unknown/run", nil)
+				assert.Nil(t, err)
+				req.Header.Set("Content-Type", "application/json")
+				if bearer != "" {
+					req.Header.Set("Authorization", "Bearer "+bearer)
+				}
+
+				res, err := server.Client().Do(req)
+				assert.Nil(t, err)
+				res.Body.Close()
+				assert.Equal(t, code, res.StatusCode, bearer)
+			}
+		})
+
+		t.Run("Token", func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "token")
+			assert.Nil(t, os.WriteFile(path, []byte("secret\n"), 0o600))
+			config, generated, err := adminConfig(":8080", path, "ignored")
+			assert.Nil(t, err)
+			assert.Empty(t, generated)
+
+			server := httptest.NewServer(dashboard.NewHandler(scheduler.NewScheduler(), config))
+			defer server.Close()
+			for token, code := range map[string]int{"": http.StatusUnauthorized, "ignored": http.StatusUnauthorized, "secret": http.StatusOK} {
+				req, err := http.NewRequest(http.MethodGet, server.URL+"/api/jobs", nil)
+				assert.Nil(t, err)
+				if token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+
+				res, err := server.Client().Do(req)
+				assert.Nil(t, err)
+				res.Body.Close()
+				assert.Equal(t, code, res.StatusCode, token)
+			}
+
+			config, _, err = adminConfig("0.0.0.0:8080", "", "from-env")
+			assert.Nil(t, err)
+			assert.Len(t, config.Admin.Authenticators, 1)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Public address without token", func(t *testing.T) {
+			t.Parallel()
+			for _, addr := range []string{":8080", "0.0.0.0:8080", "192.0.2.1:8080", "example.com:8080"} {
+				_, _, err := adminConfig(addr, "", "")
+				assert.ErrorContains(t, err, "is not a loopback address", addr)
+			}
+
+			stderr := &bytes.Buffer{}
+			code := run(context.Background(), []string{"-config", "jobs.yaml", "-admin", ":8080"}, stderr)
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, stderr.String(), "-admin :8080 is not a loopback address")
+		})
+
+		t.Run("TLS flags", func(t *testing.T) {
+			t.Parallel()
+			stderr := &bytes.Buffer{}
+			code := run(context.Background(), []string{"-config", "jobs.yaml", "-admin", "127.0.0.1:0", "-admin-cert", "cert.pem"}, stderr)
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, stderr.String(), "-admin-cert and -admin-key are set together")
+		})
+	})
+}
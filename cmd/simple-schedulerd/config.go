@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	actionLog     = "log"
+	actionCommand = "command"
+	actionWebhook = "webhook"
+)
+
+// Config is the file read by the daemon, in YAML or TOML depending on its
+// extension.
+type Config struct {
+	// TimeZone is the time zone of the cron schedules, default to UTC.
+	TimeZone string       `yaml:"time_zone" toml:"time_zone"`
+	Jobs     []*JobConfig `yaml:"jobs" toml:"jobs"`
+}
+
+// JobConfig schedules Action by exactly one of Cron, Interval, a duration
+// like 90s, and At, a one-shot date time.
+type JobConfig struct {
+	Key      string       `yaml:"key" toml:"key"`
+	Cron     string       `yaml:"cron" toml:"cron"`
+	Interval string       `yaml:"interval" toml:"interval"`
+	At       time.Time    `yaml:"at" toml:"at"`
+	Tags     []string     `yaml:"tags" toml:"tags"`
+	Action   ActionConfig `yaml:"action" toml:"action"`
+}
+
+// ActionConfig is run by a job. The log action logs Message, the command
+// action runs Command with Args, Env added to the environment of the daemon,
+// in Dir, see scheduler.CommandJob. The webhook action sends Body to URL,
+// signed with Secret when set, see scheduler.WebhookJob.
+type ActionConfig struct {
+	Type           string   `yaml:"type" toml:"type"`
+	Message        string   `yaml:"message" toml:"message"`
+	Command        string   `yaml:"command" toml:"command"`
+	Args           []string `yaml:"args" toml:"args"`
+	Env            []string `yaml:"env" toml:"env"`
+	Dir            string   `yaml:"dir" toml:"dir"`
+	Timeout        string   `yaml:"timeout" toml:"timeout"`
+	MaxOutputBytes int      `yaml:"max_output_bytes" toml:"max_output_bytes"`
+
+	Method  string            `yaml:"method" toml:"method"`
+	URL     string            `yaml:"url" toml:"url"`
+	Headers map[string]string `yaml:"headers" toml:"headers"`
+	Body    string            `yaml:"body" toml:"body"`
+	Secret  string            `yaml:"secret" toml:"secret"`
+}
+
+// parseConfig decodes data in the format of the extension of path, unknown
+// fields are rejected so a typo is not silently ignored.
+func parseConfig(path string, data []byte) (config *Config, err error) {
+	config = &Config{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case ".toml":
+		var meta toml.MetaData
+		if meta, err = toml.Decode(string(data), config); err != nil {
+			return nil, err
+		}
+
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("unknown field %s", undecoded[0])
+		}
+	default:
+		return nil, fmt.Errorf("unknown config format %q, use .yaml, .yml or .toml", ext)
+	}
+
+	return config, config.validate()
+}
+
+func (config *Config) validate() error {
+	var (
+		errs []error
+		keys = make(map[string]struct{}, len(config.Jobs))
+	)
+
+	if _, err := time.LoadLocation(config.TimeZone); err != nil {
+		errs = append(errs, fmt.Errorf("time_zone: %w", err))
+	}
+
+	for i, job := range config.Jobs {
+		if err := job.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("job %d (%s): %w", i+1, job.Key, err))
+			continue
+		}
+
+		if _, isExists := keys[job.Key]; isExists {
+			errs = append(errs, fmt.Errorf("job %d (%s): duplicate key", i+1, job.Key))
+		}
+		keys[job.Key] = struct{}{}
+	}
+	return errors.Join(errs...)
+}
+
+func (job *JobConfig) validate() error {
+	if job.Key == "" {
+		return errors.New("key is required")
+	}
+
+	schedules := 0
+	for _, isSet := range []bool{job.Cron != "", job.Interval != "", !job.At.IsZero()} {
+		if isSet {
+			schedules++
+		}
+	}
+
+	if schedules != 1 {
+		return errors.New("exactly one of cron, interval and at is required")
+	}
+
+	if job.Interval != "" {
+		if interval, err := time.ParseDuration(job.Interval); err != nil || interval <= 0 {
+			return fmt.Errorf("invalid interval %q", job.Interval)
+		}
+	}
+
+	switch job.Action.Type {
+	case actionLog:
+	case actionCommand:
+		if job.Action.Command == "" {
+			return errors.New("command is required")
+		}
+	case actionWebhook:
+		if u, err := url.Parse(job.Action.URL); err != nil || u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid url %q", job.Action.URL)
+		}
+	default:
+		return fmt.Errorf("unknown action type %q", job.Action.Type)
+	}
+
+	if job.Action.Timeout != "" {
+		if timeout, err := time.ParseDuration(job.Action.Timeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q", job.Action.Timeout)
+		}
+	}
+	return nil
+}
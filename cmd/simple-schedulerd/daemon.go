@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+)
+
+// daemon keeps the jobs of the scheduler in sync with its config file.
+type daemon struct {
+	scheduler *scheduler.Scheduler
+	logger    *slog.Logger
+	path      string
+	hash      [sha256.Size]byte
+	timeZone  string
+	jobs      map[string]*JobConfig
+}
+
+// ReloadResult lists the keys of the jobs changed by a reload. A job whose
+// definition is unchanged keeps its timer, a job failing to be scheduled,
+// like a one-shot job in the past, is skipped.
+type ReloadResult struct {
+	Added     []string
+	Replaced  []string
+	Unchanged []string
+	Cancelled []string
+	Skipped   []string
+}
+
+type job struct {
+	config  *JobConfig
+	trigger scheduler.Trigger
+	fn      scheduler.FnScheduler
+}
+
+func newDaemon(s *scheduler.Scheduler, logger *slog.Logger, path string) *daemon {
+	return &daemon{
+		scheduler: s,
+		logger:    logger,
+		path:      path,
+		jobs:      make(map[string]*JobConfig),
+	}
+}
+
+func (d *daemon) action(key string, action ActionConfig) scheduler.FnScheduler {
+	timeout, _ := time.ParseDuration(action.Timeout)
+	switch action.Type {
+	case actionCommand:
+		job := &scheduler.CommandJob{
+			Path:           action.Command,
+			Args:           action.Args,
+			Env:            action.Env,
+			Dir:            action.Dir,
+			Timeout:        timeout,
+			MaxOutputBytes: action.MaxOutputBytes,
+		}
+		return job.Run
+	case actionWebhook:
+		job := &scheduler.WebhookJob{
+			Method:         action.Method,
+			URL:            action.URL,
+			Header:         make(http.Header, len(action.Headers)),
+			Body:           action.Body,
+			Timeout:        timeout,
+			MaxOutputBytes: action.MaxOutputBytes,
+		}
+		for name, value := range action.Headers {
+			job.Header.Set(name, value)
+		}
+
+		if action.Secret != "" {
+			job.Secret = []byte(action.Secret)
+		}
+		return job.Run
+	default:
+		return func(ctx context.Context) {
+			d.logger.Info(action.Message, slog.String("key", key))
+		}
+	}
+}
+
+// build prepares every job before any of them is scheduled, so a config
+// with an invalid job changes nothing.
+func (d *daemon) build(config *Config) (jobs []*job, err error) {
+	location, err := time.LoadLocation(config.TimeZone)
+	if err != nil {
+		return
+	}
+
+	var errs []error
+	for _, c := range config.Jobs {
+		j := &job{config: c, fn: d.action(c.Key, c.Action)}
+		switch {
+		case c.Cron != "":
+			if j.trigger, err = scheduler.ParseCron(c.Cron, location); err != nil {
+				errs = append(errs, fmt.Errorf("job %s: %w", c.Key, err))
+				continue
+			}
+		case c.Interval != "":
+			interval, _ := time.ParseDuration(c.Interval)
+			j.trigger = scheduler.NewInterval(time.Now().Add(interval), interval)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, errors.Join(errs...)
+}
+
+func (d *daemon) schedule(j *job, isReplace bool) error {
+	opt := scheduler.JobOption{Tags: j.config.Tags}
+	switch {
+	case j.trigger != nil && isReplace:
+		return d.scheduler.ReplaceTrigger(j.config.Key, j.trigger, j.fn, opt)
+	case j.trigger != nil:
+		return d.scheduler.AddTrigger(j.config.Key, j.trigger, j.fn, opt)
+	case isReplace:
+		return d.scheduler.ReplaceDateTime(j.config.Key, j.config.At, j.fn, opt)
+	default:
+		return d.scheduler.AddDate(j.config.Key, j.config.At, j.fn, opt)
+	}
+}
+
+// apply adds the new jobs, replaces the changed ones and cancels the removed
+// ones. Changing the time zone replaces every job.
+func (d *daemon) apply(config *Config) (res *ReloadResult, err error) {
+	jobs, err := d.build(config)
+	if err != nil {
+		return
+	}
+
+	res = &ReloadResult{}
+	seen := make(map[string]struct{}, len(jobs))
+	for _, j := range jobs {
+		seen[j.config.Key] = struct{}{}
+	}
+
+	for key := range d.jobs {
+		if _, isExists := seen[key]; isExists {
+			continue
+		}
+
+		delete(d.jobs, key)
+		if err := d.scheduler.Cancel(key); err == nil || errors.Is(err, scheduler.ErrKeyIsNotExists) {
+			res.Cancelled = append(res.Cancelled, key)
+		}
+	}
+
+	isTimeZoneChanged := d.timeZone != config.TimeZone
+	d.timeZone = config.TimeZone
+	for _, j := range jobs {
+		key := j.config.Key
+		previous, isExists := d.jobs[key]
+		if isExists && !isTimeZoneChanged && reflect.DeepEqual(previous, j.config) {
+			res.Unchanged = append(res.Unchanged, key)
+			continue
+		}
+
+		err := d.schedule(j, isExists)
+		if isExists && errors.Is(err, scheduler.ErrKeyIsNotExists) {
+			err = d.schedule(j, false)
+		}
+
+		switch {
+		case err != nil:
+			// A failed replace leaves the previous job scheduled, it is
+			// cancelled so the job runs neither definition. The previous
+			// definition is kept when it cannot be cancelled.
+			errCancel := scheduler.ErrKeyIsNotExists
+			if isExists {
+				errCancel = d.scheduler.Cancel(key)
+			}
+
+			if errCancel == nil || errors.Is(errCancel, scheduler.ErrKeyIsNotExists) {
+				delete(d.jobs, key)
+			}
+			d.logger.Warn("job skipped", slog.String("key", key), slog.Any("error", err))
+			res.Skipped = append(res.Skipped, key)
+			continue
+		case isExists:
+			res.Replaced = append(res.Replaced, key)
+		default:
+			res.Added = append(res.Added, key)
+		}
+		d.jobs[key] = j.config
+	}
+	return res, nil
+}
+
+// reload reads the config file and applies it when its content changed or
+// force is set. The jobs are left as they are when the file is invalid.
+func (d *daemon) reload(force bool) (res *ReloadResult, err error) {
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return
+	}
+
+	hash := sha256.Sum256(data)
+	if !force && hash == d.hash {
+		return
+	}
+
+	config, err := parseConfig(d.path, data)
+	if err != nil {
+		return
+	}
+
+	if res, err = d.apply(config); err != nil {
+		return
+	}
+
+	d.hash = hash
+	d.logger.Info("config loaded", slog.String("path", d.path),
+		slog.Any("added", res.Added), slog.Any("replaced", res.Replaced),
+		slog.Any("cancelled", res.Cancelled), slog.Int("unchanged", len(res.Unchanged)),
+		slog.Any("skipped", res.Skipped))
+	return
+}
+
+// run reloads the config file on SIGHUP and, when watchInterval is set, when
+// its content changes, until ctx is done.
+func (d *daemon) run(ctx context.Context, watchInterval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var tick <-chan time.Time
+	if watchInterval > 0 {
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			_, err = d.reload(true)
+		case <-tick:
+			_, err = d.reload(false)
+		}
+
+		if err != nil {
+			d.logger.Error("config not loaded", slog.String("path", d.path), slog.Any("error", err))
+		}
+	}
+}
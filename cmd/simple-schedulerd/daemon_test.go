@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlConfig = `
+time_zone: Asia/Jakarta
+jobs:
+  - key: report
+    cron: "0 9 * * mon-fri"
+    tags: [daily]
+    action:
+      type: log
+      message: sending report
+  - key: heartbeat
+    interval: 1h
+    action:
+      type: log
+      message: alive
+  - key: migrate
+    at: 2099-01-01T00:00:00Z
+    action:
+      type: command
+      command: echo
+      args: [migrate]
+      timeout: 1m
+`
+
+const tomlConfig = `
+time_zone = "Asia/Jakarta"
+
+[[jobs]]
+key = "report"
+cron = "0 9 * * mon-fri"
+tags = ["daily"]
+action = { type = "log", message = "sending report" }
+
+[[jobs]]
+key = "heartbeat"
+interval = "1h"
+action = { type = "log", message = "alive" }
+
+[[jobs]]
+key = "migrate"
+at = 2099-01-01T00:00:00Z
+action = { type = "command", command = "echo", args = ["migrate"], timeout = "1m" }
+`
+
+func newTestDaemon(t *testing.T, name, data string) (*daemon, *scheduler.Scheduler) {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(data), 0o600))
+	s := scheduler.NewScheduler()
+	t.Cleanup(s.Stop)
+	return newDaemon(s, slog.New(slog.NewTextHandler(io.Discard, nil)), path), s
+}
+
+func sorted(keys []string) []string {
+	sort.Strings(keys)
+	return keys
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("YAML and TOML", func(t *testing.T) {
+			t.Parallel()
+			fromYAML, err := parseConfig("jobs.yaml", []byte(yamlConfig))
+			assert.Nil(t, err)
+			fromTOML, err := parseConfig("jobs.toml", []byte(tomlConfig))
+			assert.Nil(t, err)
+			assert.Equal(t, fromYAML, fromTOML)
+			assert.Len(t, fromYAML.Jobs, 3)
+			assert.Equal(t, []string{"migrate"}, fromYAML.Jobs[2].Action.Args)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Invalid jobs", func(t *testing.T) {
+			t.Parallel()
+			_, err := parseConfig("jobs.yaml", []byte(`
+time_zone: Mars/Olympus
+jobs:
+  - cron: "* * * * *"
+    action: {type: log}
+  - key: a
+    cron: "* * * * *"
+    interval: 1m
+    action: {type: log}
+  - key: b
+    interval: -1m
+    action: {type: log}
+  - key: c
+    interval: 1m
+    action: {type: command}
+  - key: c
+    interval: 1m
+    action: {type: mail}
+  - key: d
+    interval: 1m
+    action: {type: command, command: echo, timeout: soon}
+  - key: e
+    interval: 1m
+    action: {type: webhook, url: "ftp://example.com"}
+`))
+			assert.ErrorContains(t, err, "time_zone")
+			assert.ErrorContains(t, err, "job 1 (): key is required")
+			assert.ErrorContains(t, err, "job 2 (a): exactly one of cron, interval and at is required")
+			assert.ErrorContains(t, err, `job 3 (b): invalid interval "-1m"`)
+			assert.ErrorContains(t, err, "job 4 (c): command is required")
+			assert.ErrorContains(t, err, `job 5 (c): unknown action type "mail"`)
+			assert.ErrorContains(t, err, `job 6 (d): invalid timeout "soon"`)
+			assert.ErrorContains(t, err, `job 7 (e): invalid url "ftp://example.com"`)
+		})
+
+		t.Run("Unknown field or format", func(t *testing.T) {
+			t.Parallel()
+			_, err := parseConfig("jobs.yaml", []byte("jobs:\n  - key: a\n    crom: \"* * * * *\"\n"))
+			assert.ErrorContains(t, err, "crom")
+			_, err = parseConfig("jobs.toml", []byte("[[jobs]]\nkey = \"a\"\ncrom = \"* * * * *\"\n"))
+			assert.ErrorContains(t, err, "unknown field jobs.crom")
+			_, err = parseConfig("jobs.json", []byte("{}"))
+			assert.ErrorContains(t, err, "unknown config format")
+		})
+	})
+}
+
+func TestDaemon(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Load", func(t *testing.T) {
+			t.Parallel()
+			d, s := newTestDaemon(t, "jobs.toml", tomlConfig)
+			res, err := d.reload(true)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"heartbeat", "migrate", "report"}, sorted(res.Added))
+
+			report, err := s.Get("report")
+			assert.Nil(t, err)
+			assert.Equal(t, "0 9 * * mon-fri", report.Trigger)
+			assert.Equal(t, []string{"daily"}, report.Tags)
+			assert.Equal(t, 9, report.DateTime.In(time.FixedZone("WIB", 7*3600)).Hour())
+
+			migrate, err := s.Get("migrate")
+			assert.Nil(t, err)
+			assert.True(t, migrate.DateTime.Equal(time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)))
+		})
+
+		t.Run("Apply diff", func(t *testing.T) {
+			t.Parallel()
+			d, s := newTestDaemon(t, "jobs.yaml", yamlConfig)
+			_, err := d.reload(true)
+			assert.Nil(t, err)
+			heartbeat, err := s.Get("heartbeat")
+			assert.Nil(t, err)
+
+			res, err := d.reload(false)
+			assert.Nil(t, err)
+			assert.Nil(t, res)
+
+			config, err := parseConfig("jobs.yaml", []byte(yamlConfig))
+			assert.Nil(t, err)
+			config.Jobs[0].Cron = "30 9 * * mon-fri"
+			config.Jobs[2] = &JobConfig{Key: "cleanup", Interval: "2h", Action: ActionConfig{Type: actionLog}}
+			res, err = d.apply(config)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"cleanup"}, res.Added)
+			assert.Equal(t, []string{"report"}, res.Replaced)
+			assert.Equal(t, []string{"heartbeat"}, res.Unchanged)
+			assert.Equal(t, []string{"migrate"}, res.Cancelled)
+
+			unchanged, err := s.Get("heartbeat")
+			assert.Nil(t, err)
+			assert.Equal(t, heartbeat.DateTime, unchanged.DateTime)
+			report, err := s.Get("report")
+			assert.Nil(t, err)
+			assert.Equal(t, "30 9 * * mon-fri", report.Trigger)
+			_, err = s.Get("migrate")
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, err)
+
+			config.TimeZone = "UTC"
+			res, err = d.apply(config)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"cleanup", "heartbeat", "report"}, sorted(res.Replaced))
+		})
+
+		t.Run("Reload on file change", func(t *testing.T) {
+			t.Parallel()
+			d, s := newTestDaemon(t, "jobs.yaml", yamlConfig)
+			_, err := d.reload(true)
+			assert.Nil(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				d.run(ctx, 10*time.Millisecond)
+				close(done)
+			}()
+			t.Cleanup(func() {
+				cancel()
+				<-done
+			})
+
+			assert.Nil(t, os.WriteFile(d.path, []byte("jobs: [{key: a, interval: 1m, action: {type: log}}]"), 0o600))
+			assert.Eventually(t, func() bool {
+				_, err := s.Get("report")
+				return err == scheduler.ErrKeyIsNotExists
+			}, time.Second, 10*time.Millisecond)
+			_, err = s.Get("a")
+			assert.Nil(t, err)
+		})
+
+		t.Run("Command", func(t *testing.T) {
+			t.Parallel()
+			d, s := newTestDaemon(t, "jobs.yaml", yamlConfig)
+			_, err := d.reload(true)
+			assert.Nil(t, err)
+			assert.Nil(t, s.RunNow("migrate"))
+			assert.Eventually(t, func() bool {
+				return len(s.History("migrate")) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			if runtime.GOOS != "windows" {
+				assert.Equal(t, "migrate\n", s.History("migrate")[0].Output)
+			}
+		})
+
+		t.Run("Webhook", func(t *testing.T) {
+			t.Parallel()
+			requests := make(chan *http.Request, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests <- r
+			}))
+			defer server.Close()
+
+			d, s := newTestDaemon(t, "jobs.yaml", fmt.Sprintf(`
+jobs:
+  - key: notify
+    interval: 1h
+    action:
+      type: webhook
+      url: %s/notify
+      headers: {X-Tenant: acme}
+      body: '{"key":{{json .Key}}}'
+      secret: secret
+`, server.URL))
+			_, err := d.reload(true)
+			assert.Nil(t, err)
+			assert.Nil(t, s.RunNow("notify"))
+
+			r := <-requests
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/notify", r.URL.Path)
+			assert.Equal(t, "acme", r.Header.Get("X-Tenant"))
+			job := &scheduler.WebhookJob{Secret: []byte("secret")}
+			assert.Equal(t, job.Sign([]byte(`{"key":"notify"}`)), r.Header.Get(scheduler.HeaderWebhookSignature))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Invalid reload keeps the jobs", func(t *testing.T) {
+			t.Parallel()
+			d, s := newTestDaemon(t, "jobs.yaml", yamlConfig)
+			_, err := d.reload(true)
+			assert.Nil(t, err)
+
+			assert.Nil(t, os.WriteFile(d.path, []byte("jobs: [{key: report, cron: nope, action: {type: log}}]"), 0o600))
+			_, err = d.reload(false)
+			assert.ErrorIs(t, err, scheduler.ErrCronFormat)
+			_, err = s.Get("heartbeat")
+			assert.Nil(t, err)
+		})
+
+		t.Run("Failed replace cancels the previous job", func(t *testing.T) {
+			t.Parallel()
+			d, s := newTestDaemon(t, "jobs.yaml", yamlConfig)
+			_, err := d.reload(true)
+			assert.Nil(t, err)
+
+			config, err := parseConfig("jobs.yaml", []byte(yamlConfig))
+			assert.Nil(t, err)
+			config.Jobs[2].At = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			res, err := d.apply(config)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"migrate"}, res.Skipped)
+			_, err = s.Get("migrate")
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, err)
+
+			config.Jobs[2].At = time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+			res, err = d.apply(config)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"migrate"}, res.Added)
+		})
+
+		t.Run("One-shot job in the past", func(t *testing.T) {
+			t.Parallel()
+			d, _ := newTestDaemon(t, "jobs.yaml", "jobs: [{key: old, at: 2000-01-01T00:00:00Z, action: {type: log}}]")
+			res, err := d.reload(true)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"old"}, res.Skipped)
+		})
+	})
+}
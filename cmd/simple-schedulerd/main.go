@@ -0,0 +1,110 @@
+// Command simple-schedulerd runs the jobs defined by a YAML or TOML config
+// file. The file is reloaded on SIGHUP and when its content changes, only
+// the jobs whose definition changed are added, replaced or cancelled.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/sodri126/go-simple-scheduler/dashboard"
+)
+
+const (
+	exitFailure = 1
+	exitUsage   = 2
+
+	shutdownTimeout = 10 * time.Second
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	var (
+		flags         = flag.NewFlagSet("simple-schedulerd", flag.ContinueOnError)
+		path          = flags.String("config", "", "`path` of the YAML or TOML config file")
+		watchInterval = flags.Duration("watch-interval", 2*time.Second, "interval checking the config file for changes, 0 disables it")
+		admin         = flags.String("admin", "", "`address` serving the dashboard and the admin API under /api/, disabled by default")
+		tokenFile     = flags.String("admin-token-file", "", "`path` of the bearer token of the admin API, default to $"+envAdminToken+", required unless -admin is a loopback address")
+		cert          = flags.String("admin-cert", "", "`path` of the TLS certificate of the admin API")
+		key           = flags.String("admin-key", "", "`path` of the TLS key of the admin API")
+	)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *path == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var (
+		adminConf  dashboard.Config
+		adminToken string
+	)
+	if *admin != "" {
+		var err error
+		if adminConf, adminToken, err = adminConfig(*admin, *tokenFile, os.Getenv(envAdminToken)); err == nil {
+			err = checkTLSFlags(*cert, *key)
+		}
+
+		if err != nil {
+			fmt.Fprintf(stderr, "simple-schedulerd: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	logger := slog.New(slog.NewTextHandler(stderr, nil))
+	s := scheduler.NewScheduler(scheduler.Config{Logger: logger})
+	defer s.Stop()
+
+	d := newDaemon(s, logger, *path)
+	if _, err := d.reload(true); err != nil {
+		fmt.Fprintf(stderr, "simple-schedulerd: %v\n", err)
+		return exitFailure
+	}
+
+	if *admin != "" {
+		if adminToken != "" {
+			fmt.Fprintf(stderr, "simple-schedulerd: admin token %s\n", adminToken)
+		}
+
+		server := &http.Server{Addr: *admin, Handler: dashboard.NewHandler(s, adminConf)}
+		go func() {
+			var err error
+			if *cert != "" {
+				err = server.ListenAndServeTLS(*cert, *key)
+			} else {
+				err = server.ListenAndServe()
+			}
+
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("admin server stopped", slog.Any("error", err))
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+	}
+
+	d.run(ctx, *watchInterval)
+	return 0
+}
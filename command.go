@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxOutputBytes = 64 << 10
+
+	// commandWaitDelay bounds the wait for the output pipes once the
+	// command is killed, a process escaping the process group may keep them
+	// open.
+	commandWaitDelay = time.Second
+)
+
+// CommandJob runs an executable, its Run method is the function of a job.
+// The output of the command is recorded in the run history and a non-zero
+// exit status fails the run. The process group of the command is killed
+// after Timeout or when the context of the job is done, like when the job
+// is cancelled.
+type CommandJob struct {
+	Path string
+	Args []string
+	// Env is added to the environment of the scheduler.
+	Env     []string
+	Dir     string
+	Stdin   string
+	Timeout time.Duration
+	// MaxOutputBytes bounds the recorded stdout and stderr, default to
+	// 64 KiB. The rest of the output is discarded.
+	MaxOutputBytes int
+}
+
+func (job *CommandJob) Run(ctx context.Context) {
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	limit := job.MaxOutputBytes
+	if limit <= 0 {
+		limit = defaultMaxOutputBytes
+	}
+
+	output := &limitedBuffer{limit: limit}
+	cmd := exec.CommandContext(ctx, job.Path, job.Args...)
+	cmd.Env = append(os.Environ(), job.Env...)
+	cmd.Dir = job.Dir
+	if job.Stdin != "" {
+		cmd.Stdin = strings.NewReader(job.Stdin)
+	}
+	cmd.Stdout, cmd.Stderr = output, output
+	cmd.WaitDelay = commandWaitDelay
+	setProcessGroup(cmd)
+
+	err := cmd.Run()
+	SetOutput(ctx, output.String())
+	if err == nil {
+		return
+	}
+
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	Fail(ctx, fmt.Errorf("%w: %v", ErrCommandFailed, err))
+}
+
+// limitedBuffer keeps the first limit bytes written to it and counts the
+// discarded ones.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	discarded int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := min(len(p), max(b.limit-b.buf.Len(), 0))
+	b.buf.Write(p[:n])
+	b.discarded += len(p) - n
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.discarded == 0 {
+		return b.buf.String()
+	}
+
+	return fmt.Sprintf("%s\n[%d bytes discarded]", b.buf.String(), b.discarded)
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package scheduler
+
+import "os/exec"
+
+// setProcessGroup leaves cmd as is, only the process of cmd is killed when
+// it is cancelled.
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runCommand runs job once with a scheduler and returns its run.
+func runCommand(t *testing.T, job *CommandJob) *Run {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are run with sh")
+	}
+
+	schedule := NewScheduler()
+	assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, job.Run))
+	assert.Eventually(t, func() bool {
+		return len(schedule.History("add#1")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	return schedule.History("add#1")[0]
+}
+
+func TestCommandJob(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Capture output", func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			assert.Nil(t, os.WriteFile(filepath.Join(dir, "marker"), nil, 0o600))
+			run := runCommand(t, &CommandJob{
+				Path: "sh",
+				Args: []string{"-c", `echo "$GREETING"; ls; echo err >&2`},
+				Env:  []string{"GREETING=hello"},
+				Dir:  dir,
+			})
+			assert.Equal(t, OutcomeSuccess, run.Outcome)
+			assert.Equal(t, "hello\nmarker\nerr\n", run.Output)
+		})
+
+		t.Run("Limit output", func(t *testing.T) {
+			t.Parallel()
+			run := runCommand(t, &CommandJob{Path: "sh", Args: []string{"-c", "printf 0123456789"}, MaxOutputBytes: 4})
+			assert.Equal(t, "0123\n[6 bytes discarded]", run.Output)
+		})
+
+		t.Run("Cancel kills the command", func(t *testing.T) {
+			t.Parallel()
+			if runtime.GOOS == "windows" {
+				t.Skip("the commands are run with sh")
+			}
+
+			schedule := NewScheduler()
+			job := &CommandJob{Path: "sh", Args: []string{"-c", "sleep 30 & wait"}}
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, job.Run))
+			assert.Eventually(t, func() bool {
+				return schedule.Running() == 1
+			}, time.Second, 5*time.Millisecond)
+
+			assert.Nil(t, schedule.Cancel("add#1"))
+			assert.Eventually(t, func() bool {
+				return len(schedule.History("add#1")) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			run := schedule.History("add#1")[0]
+			assert.ErrorIs(t, run.Err, ErrCommandFailed)
+			assert.ErrorContains(t, run.Err, context.Canceled.Error())
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Non-zero exit status", func(t *testing.T) {
+			t.Parallel()
+			run := runCommand(t, &CommandJob{Path: "sh", Args: []string{"-c", "echo failed; exit 3"}})
+			assert.Equal(t, OutcomeFailure, run.Outcome)
+			assert.ErrorIs(t, run.Err, ErrCommandFailed)
+			assert.ErrorContains(t, run.Err, "exit status 3")
+			assert.Equal(t, "failed\n", run.Output)
+		})
+
+		t.Run("Timeout kills the process group", func(t *testing.T) {
+			t.Parallel()
+			// The background sleep keeps the output open, the run only ends
+			// before commandWaitDelay when the whole group is killed.
+			run := runCommand(t, &CommandJob{
+				Path:    "sh",
+				Args:    []string{"-c", "echo started; sleep 30 & sleep 30"},
+				Timeout: 100 * time.Millisecond,
+			})
+			assert.Equal(t, OutcomeFailure, run.Outcome)
+			assert.ErrorContains(t, run.Err, context.DeadlineExceeded.Error())
+			assert.Equal(t, "started\n", run.Output)
+			assert.Less(t, run.EndTime.Sub(run.StartTime), commandWaitDelay)
+		})
+
+		t.Run("Unknown executable", func(t *testing.T) {
+			t.Parallel()
+			run := runCommand(t, &CommandJob{Path: "simple-scheduler-unknown-executable"})
+			assert.ErrorIs(t, run.Err, ErrCommandFailed)
+		})
+	})
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package scheduler
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in a new process group, the process tree of cmd
+// is killed with taskkill when cmd is cancelled.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+	cmd.Cancel = func() error {
+		err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+		if err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+}
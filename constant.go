@@ -5,9 +5,29 @@ import (
 )
 
 var (
-	ErrKeyIsExists         = errors.New("the key is exists")
-	ErrDateTimeLessThanNow = errors.New("the parameter date time cannot less than now")
-	ErrKeyIsNotExists      = errors.New("the key is not exists")
+	ErrKeyIsExists          = errors.New("the key is exists")
+	ErrDateTimeLessThanNow  = errors.New("the parameter date time cannot less than now")
+	ErrKeyIsNotExists       = errors.New("the key is not exists")
+	ErrHandlerIsExists      = errors.New("the handler is exists")
+	ErrHandlerIsNotExists   = errors.New("the handler is not exists")
+	ErrSnapshotFormat       = errors.New("the snapshot format is invalid")
+	ErrSnapshotVersion      = errors.New("the snapshot version is not supported")
+	ErrJobPanic             = errors.New("the job is panic")
+	ErrLeaseIsNotHeld       = errors.New("the lease is not held")
+	ErrSchedulerIsNotLeader = errors.New("the scheduler is not leader")
+	ErrExecutionIsLocked    = errors.New("the execution is locked")
+	ErrKeyIsPaused          = errors.New("the key is paused")
+	ErrKeyIsNotPaused       = errors.New("the key is not paused")
+	ErrKeyIsRunning         = errors.New("the key is running")
+	ErrQueryCursor          = errors.New("the query cursor is invalid")
+	ErrRRuleFormat          = errors.New("the rrule format is invalid")
+	ErrTriggerIsExhausted   = errors.New("the trigger has no next date time")
+	ErrICalFormat           = errors.New("the icalendar format is invalid")
+	ErrCronFormat           = errors.New("the cron format is invalid")
+	ErrCommandFailed        = errors.New("the command is failed")
+	ErrWebhookFailed        = errors.New("the webhook is failed")
+	ErrWebhookStatus        = errors.New("the webhook status is unsuccessful")
+	ErrCrontabFormat        = errors.New("the crontab format is invalid")
 )
 
 type ListType int
@@ -15,4 +35,42 @@ type ListType int
 const (
 	ListTypeDefault ListType = iota
 	ListTypeJSON
+	ListTypeCSV
+	ListTypeYAML
+	ListTypeMarkdown
+	ListTypeHTML
+	ListTypeICal
+)
+
+type ListColumn string
+
+const (
+	ListColumnNo       ListColumn = "No."
+	ListColumnKey      ListColumn = "Key"
+	ListColumnDateTime ListColumn = "Date Time"
+	ListColumnHandler  ListColumn = "Handler"
+	ListColumnStatus   ListColumn = "Status"
+	ListColumnTags     ListColumn = "Tags"
+)
+
+type QuerySort int
+
+const (
+	QuerySortDateTime QuerySort = iota
+	QuerySortKey
+)
+
+type SnapshotFormat int
+
+const (
+	SnapshotFormatJSON SnapshotFormat = iota
+	SnapshotFormatBinary
+)
+
+type RestoreStrategy int
+
+const (
+	RestoreStrategyFail RestoreStrategy = iota
+	RestoreStrategySkip
+	RestoreStrategyOverwrite
 )
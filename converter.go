@@ -1,5 +1,80 @@
 package scheduler
 
+import (
+	"bytes"
+	"io"
+)
+
 type ListConverter interface {
 	Convert(data []*ResponseScheduler) ([]byte, error)
 }
+
+// ResponseIterator returns the next job to render, ok is false once every
+// job has been returned.
+type ResponseIterator func() (res *ResponseScheduler, ok bool)
+
+// StreamConverter is implemented by the converters able to render jobs one
+// by one, List uses it instead of Convert so the whole list is never kept
+// in memory.
+type StreamConverter interface {
+	ListConverter
+	Stream(w io.Writer, next ResponseIterator) (n int, err error)
+}
+
+func iterateResponse(data []*ResponseScheduler) ResponseIterator {
+	return func() (res *ResponseScheduler, ok bool) {
+		if len(data) == 0 {
+			return
+		}
+
+		res, data = data[0], data[1:]
+		return res, true
+	}
+}
+
+func writeList(w io.Writer, next ResponseIterator, lc ListConverter) (n int, err error) {
+	if sc, isStream := lc.(StreamConverter); isStream {
+		return sc.Stream(w, next)
+	}
+
+	var data []*ResponseScheduler
+	for res, ok := next(); ok; res, ok = next() {
+		data = append(data, res)
+	}
+
+	bytes, err := lc.Convert(data)
+	if err != nil {
+		return
+	}
+
+	return w.Write(bytes)
+}
+
+func convertStream(sc StreamConverter, data []*ResponseScheduler) (res []byte, err error) {
+	buf := &bytes.Buffer{}
+	_, err = sc.Stream(buf, iterateResponse(data))
+	res = buf.Bytes()
+	return
+}
+
+// countWriter counts the written bytes and keeps the first error so the
+// converters can check it once at the end.
+type countWriter struct {
+	w   io.Writer
+	n   int
+	err error
+}
+
+func (c *countWriter) Write(p []byte) (n int, err error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, c.err = c.w.Write(p)
+	c.n += n
+	return n, c.err
+}
+
+func (c *countWriter) WriteString(s string) {
+	_, _ = c.Write([]byte(s))
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stretchr/testify/assert"
+)
+
+type errConverter struct {
+	err error
+}
+
+func (c *errConverter) Convert(data []*ResponseScheduler) ([]byte, error) {
+	return nil, c.err
+}
+
+type errWriter struct {
+	err error
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+type funcWriter func(p []byte) (int, error)
+
+func (fn funcWriter) Write(p []byte) (int, error) {
+	return fn(p)
+}
+
+func TestStreamConverter(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Repeated default convert", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+
+			lc := NewDefaultResponse()
+			first, second := &bytes.Buffer{}, &bytes.Buffer{}
+			_, err := schedule.List(first, lc)
+			assert.Nil(t, err)
+			_, err = schedule.List(second, lc)
+			assert.Nil(t, err)
+			assert.Equal(t, first.String(), second.String())
+		})
+
+		t.Run("Render without holding the scheduler", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			for i := 0; i < 2*listBatchSize; i++ {
+				assert.Nil(t, schedule.Add(fmt.Sprintf("add#%d", i), time.Hour, fn))
+			}
+
+			added := 0
+			w := funcWriter(func(p []byte) (int, error) {
+				if added < 10 {
+					assert.Nil(t, schedule.Add(fmt.Sprintf("write#%d", added), time.Hour, fn))
+					added++
+				}
+				return len(p), nil
+			})
+
+			n, err := schedule.List(w, NewListConverter(ListTypeCSV))
+			assert.Nil(t, err)
+			assert.Greater(t, n, 0)
+		})
+
+		t.Run("Default table in batches", func(t *testing.T) {
+			t.Parallel()
+			dateTime := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+			var data []*ResponseScheduler
+			for i := 0; i < 2*listBatchSize+1; i++ {
+				data = append(data, &ResponseScheduler{Key: fmt.Sprintf("add#%05d", i), Time: dateTime})
+			}
+
+			tw := table.NewWriter()
+			tw.AppendHeader(table.Row{"No.", "Key", "Date Time"})
+			for i := 0; i < len(data); i++ {
+				tw.AppendRow(table.Row{i + 1, data[i].Key, data[i].Time})
+			}
+
+			res, err := NewDefaultResponse().Convert(data)
+			assert.Nil(t, err)
+			assert.Equal(t, tw.Render(), string(res))
+
+			buf := &bytes.Buffer{}
+			_, err = NewDefaultResponse().(StreamConverter).Stream(buf, iterateResponse(data))
+			assert.Nil(t, err)
+			assert.Equal(t, tw.Render(), buf.String())
+
+			data[len(data)-1].Key += "-longer"
+			res, err = NewDefaultResponse().Convert(data)
+			assert.Nil(t, err)
+			lines := strings.Split(string(res), "\n")
+			assert.Len(t, lines, len(data)+4)
+			for _, line := range lines {
+				assert.Equal(t, len(lines[0]), len(line))
+			}
+		})
+
+		t.Run("List with bounded memory", func(t *testing.T) {
+			if testing.Short() {
+				t.Skip("skipping in short mode")
+			}
+
+			total := 200000
+			schedule := NewScheduler()
+			for i := 0; i < total; i++ {
+				assert.Nil(t, schedule.Add(fmt.Sprintf("add#%07d", i), time.Duration(total-i)*time.Second+time.Hour, fn))
+			}
+
+			var (
+				rows      int
+				partial   string
+				previous  string
+				base, mem runtime.MemStats
+			)
+			runtime.GC()
+			runtime.ReadMemStats(&base)
+			w := funcWriter(func(p []byte) (int, error) {
+				lines := strings.Split(partial+string(p), "\n")
+				partial = lines[len(lines)-1]
+				for _, key := range lines[:len(lines)-1] {
+					if key == "Key" {
+						continue
+					}
+
+					// The jobs are added in the reverse order of their date time.
+					assert.True(t, previous == "" || key < previous, key)
+					previous = key
+					if rows++; rows%(total/4) == 0 {
+						runtime.GC()
+						runtime.ReadMemStats(&mem)
+						assert.Less(t, int64(mem.HeapAlloc)-int64(base.HeapAlloc), int64(8<<20))
+					}
+				}
+				return len(p), nil
+			})
+
+			start := time.Now()
+			_, err := schedule.List(w, NewListConverter(ListTypeCSV, ListConfig{Columns: []ListColumn{ListColumnKey}}))
+			assert.Nil(t, err)
+			assert.Equal(t, total, rows)
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Propagate errors", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			failure := errors.New("failure")
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+
+			_, err := schedule.List(&bytes.Buffer{}, &errConverter{err: failure})
+			assert.Equal(t, failure, err)
+
+			for _, listType := range []ListType{ListTypeDefault, ListTypeJSON, ListTypeCSV, ListTypeYAML, ListTypeMarkdown, ListTypeHTML, ListTypeICal} {
+				_, err = schedule.List(&errWriter{err: failure}, NewListConverter(listType))
+				assert.ErrorIs(t, err, failure)
+			}
+		})
+
+		t.Run("Stop on the first write error", func(t *testing.T) {
+			t.Parallel()
+			failure := errors.New("failure")
+			for _, listType := range []ListType{ListTypeDefault, ListTypeJSON, ListTypeCSV, ListTypeYAML, ListTypeMarkdown, ListTypeHTML, ListTypeICal} {
+				calls := 0
+				next := func() (*ResponseScheduler, bool) {
+					calls++
+					return &ResponseScheduler{Key: "add#1", Time: time.Now()}, true
+				}
+
+				_, err := NewListConverter(listType).(StreamConverter).Stream(&errWriter{err: failure}, next)
+				assert.ErrorIs(t, err, failure, listType)
+				assert.LessOrEqual(t, calls, listBatchSize+1, listType)
+			}
+		})
+	})
+}
+
+func BenchmarkList(b *testing.B) {
+	schedule := NewScheduler()
+	for i := 0; i < 1000000; i++ {
+		if err := schedule.Add(fmt.Sprintf("add#%07d", i), time.Duration(i)*time.Second+time.Hour, fn); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := schedule.List(io.Discard, NewListConverter(ListTypeCSV)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
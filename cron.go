@@ -0,0 +1,235 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// cronMaxYears bounds the search of a date time, a schedule like the
+	// 29th of February matches at least once in 8 years.
+	cronMaxYears = 10
+)
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronFields = [...]cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		}},
+		{name: "day of week", min: 0, max: 7, names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		}},
+	}
+
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+const (
+	cronMinute = iota
+	cronHour
+	cronDayOfMonth
+	cronMonth
+	cronDayOfWeek
+)
+
+// Cron is a Trigger following a crontab schedule: minute, hour, day of
+// month, month and day of week, or one of the @yearly, @annually, @monthly,
+// @weekly, @daily, @midnight and @hourly descriptors. A job whose day of
+// month and day of week are both restricted runs when either matches. A
+// date time falling in a daylight saving gap is skipped and one repeated by
+// a daylight saving overlap runs once.
+type Cron struct {
+	spec     string
+	location *time.Location
+	fields   [len(cronFields)]uint64
+
+	dayOfMonthStar bool
+	dayOfWeekStar  bool
+}
+
+// ParseCron parses spec in location, default to UTC. A CRON_TZ= or TZ=
+// prefix overrides location, like in "CRON_TZ=Asia/Jakarta 0 9 * * mon-fri".
+func ParseCron(spec string, location *time.Location) (c *Cron, err error) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	fields := strings.Fields(spec)
+	c = &Cron{spec: strings.Join(fields, " "), location: location}
+	if len(fields) > 0 {
+		if name, isFound := cutPrefixes(fields[0], "CRON_TZ=", "TZ="); isFound {
+			if c.location, err = time.LoadLocation(name); err != nil {
+				return nil, fmt.Errorf("%w: time zone %q: %v", ErrCronFormat, name, err)
+			}
+			fields = fields[1:]
+		}
+	}
+
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		descriptor, isExists := cronDescriptors[strings.ToLower(fields[0])]
+		if !isExists {
+			return nil, fmt.Errorf("%w: unknown descriptor %q", ErrCronFormat, fields[0])
+		}
+		fields = strings.Fields(descriptor)
+	}
+
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("%w: %d fields instead of %d in %q", ErrCronFormat, len(fields), len(cronFields), spec)
+	}
+
+	for i := range cronFields {
+		if c.fields[i], err = cronFields[i].parse(fields[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.fields[cronDayOfWeek]&(1<<7) != 0 {
+		c.fields[cronDayOfWeek] = c.fields[cronDayOfWeek]&^(1<<7) | 1
+	}
+
+	c.dayOfMonthStar = strings.HasPrefix(fields[cronDayOfMonth], "*")
+	c.dayOfWeekStar = strings.HasPrefix(fields[cronDayOfWeek], "*")
+	return
+}
+
+func cutPrefixes(s string, prefixes ...string) (string, bool) {
+	for _, prefix := range prefixes {
+		if after, isFound := strings.CutPrefix(s, prefix); isFound {
+			return after, true
+		}
+	}
+	return s, false
+}
+
+// parse returns the bit set of the values of a comma separated list of
+// values, ranges and steps.
+func (f *cronField) parse(expr string) (bits uint64, err error) {
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("%w: invalid step of %s %q", ErrCronFormat, f.name, part)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		if rangeExpr != "*" {
+			loExpr, hiExpr, isRange := strings.Cut(rangeExpr, "-")
+			if lo, err = f.value(loExpr); err != nil {
+				return
+			}
+
+			switch {
+			case isRange:
+				if hi, err = f.value(hiExpr); err != nil {
+					return
+				}
+			case !hasStep:
+				hi = lo
+			}
+		}
+
+		if lo > hi {
+			return 0, fmt.Errorf("%w: invalid range of %s %q", ErrCronFormat, f.name, part)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return
+}
+
+func (f *cronField) value(expr string) (int, error) {
+	if v, isExists := f.names[strings.ToLower(expr)]; isExists {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: %s %q is not between %d and %d", ErrCronFormat, f.name, expr, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (c *Cron) has(field, v int) bool {
+	return c.fields[field]&(1<<v) != 0
+}
+
+func (c *Cron) matchDay(year int, month time.Month, day int) bool {
+	if !c.has(cronMonth, int(month)) {
+		return false
+	}
+
+	var (
+		weekday         = time.Date(year, month, day, 12, 0, 0, 0, time.UTC).Weekday()
+		matchDayOfMonth = c.has(cronDayOfMonth, day)
+		matchDayOfWeek  = c.has(cronDayOfWeek, int(weekday))
+	)
+
+	if c.dayOfMonthStar || c.dayOfWeekStar {
+		return matchDayOfMonth && matchDayOfWeek
+	}
+	return matchDayOfMonth || matchDayOfWeek
+}
+
+func (c *Cron) Next(after time.Time) (time.Time, bool) {
+	after = after.In(c.location)
+	for i := 0; ; i++ {
+		year, month, day := time.Date(after.Year(), after.Month(), after.Day()+i, 12, 0, 0, 0, c.location).Date()
+		if year > after.Year()+cronMaxYears {
+			return time.Time{}, false
+		}
+
+		if !c.matchDay(year, month, day) {
+			continue
+		}
+
+		for hour := 0; hour < 24; hour++ {
+			if !c.has(cronHour, hour) {
+				continue
+			}
+
+			for minute := 0; minute < 60; minute++ {
+				if !c.has(cronMinute, minute) {
+					continue
+				}
+
+				next := time.Date(year, month, day, hour, minute, 0, 0, c.location)
+				if next.Hour() == hour && next.Minute() == minute && next.After(after) {
+					return next, true
+				}
+			}
+		}
+	}
+}
+
+// Location returns the time zone of the schedule.
+func (c *Cron) Location() *time.Location {
+	return c.location
+}
+
+func (c *Cron) String() string {
+	return c.spec
+}
@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func nextDateTimes(trigger Trigger, after time.Time, total int) (res []string) {
+	for i := 0; i < total; i++ {
+		next, ok := trigger.Next(after)
+		if !ok {
+			break
+		}
+
+		res = append(res, next.Format(time.RFC3339))
+		after = next
+	}
+	return
+}
+
+func TestCron(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.Nil(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	t.Run("Positive Case", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			spec     string
+			location *time.Location
+			after    string
+			expected []string
+		}{
+			{
+				name:     "Every 15 minutes",
+				spec:     "*/15 * * * *",
+				after:    "2030-01-01T10:07:00Z",
+				expected: []string{"2030-01-01T10:15:00Z", "2030-01-01T10:30:00Z", "2030-01-01T10:45:00Z"},
+			},
+			{
+				name:     "Weekdays with names",
+				spec:     "30 9 * * MON-fri",
+				after:    "2030-01-04T10:00:00Z",
+				expected: []string{"2030-01-07T09:30:00Z", "2030-01-08T09:30:00Z"},
+			},
+			{
+				name:     "Lists, ranges and steps",
+				spec:     "0 8-12/2,18 1 jan,jul *",
+				after:    "2030-01-01T09:00:00Z",
+				expected: []string{"2030-01-01T10:00:00Z", "2030-01-01T12:00:00Z", "2030-01-01T18:00:00Z", "2030-07-01T08:00:00Z"},
+			},
+			{
+				name:     "Day of month or day of week",
+				spec:     "0 0 13 * 5",
+				after:    "2030-09-01T00:00:00Z",
+				expected: []string{"2030-09-06T00:00:00Z", "2030-09-13T00:00:00Z", "2030-09-20T00:00:00Z"},
+			},
+			{
+				name:     "Sunday as 7",
+				spec:     "0 0 * * 7",
+				after:    "2030-01-01T00:00:00Z",
+				expected: []string{"2030-01-06T00:00:00Z"},
+			},
+			{
+				name:     "Descriptor",
+				spec:     "@yearly",
+				after:    "2030-06-01T00:00:00Z",
+				expected: []string{"2031-01-01T00:00:00Z"},
+			},
+			{
+				name:     "29th of February",
+				spec:     "0 0 29 2 *",
+				after:    "2030-01-01T00:00:00Z",
+				expected: []string{"2032-02-29T00:00:00Z"},
+			},
+			{
+				name:     "Time zone prefix",
+				spec:     "CRON_TZ=Asia/Jakarta 0 9 * * *",
+				after:    "2030-01-01T00:00:00Z",
+				expected: []string{"2030-01-01T09:00:00+07:00"},
+			},
+			{
+				name:     "Time zone location",
+				spec:     "0 9 * * *",
+				location: jakarta,
+				after:    "2030-01-01T03:00:00Z",
+				expected: []string{"2030-01-02T09:00:00+07:00"},
+			},
+			{
+				name:     "Skip daylight saving gap",
+				spec:     "30 2 * * *",
+				location: newYork,
+				after:    "2030-03-09T12:00:00Z",
+				expected: []string{"2030-03-11T02:30:00-04:00"},
+			},
+			{
+				name:     "Run once in daylight saving overlap",
+				spec:     "30 1 * * *",
+				location: newYork,
+				after:    "2030-11-02T12:00:00Z",
+				expected: []string{"2030-11-03T01:30:00-04:00", "2030-11-04T01:30:00-05:00"},
+			},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				cron, err := ParseCron(tc.spec, tc.location)
+				assert.Nil(t, err)
+
+				after, err := time.Parse(time.RFC3339, tc.after)
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expected, nextDateTimes(cron, after, len(tc.expected)))
+			})
+		}
+
+		t.Run("Normalize spec", func(t *testing.T) {
+			t.Parallel()
+			cron, err := ParseCron("  TZ=Asia/Jakarta   0 9  * * * ", nil)
+			assert.Nil(t, err)
+			assert.Equal(t, "TZ=Asia/Jakarta 0 9 * * *", cron.String())
+			assert.Equal(t, jakarta, cron.Location())
+		})
+
+		t.Run("Never matching spec is exhausted", func(t *testing.T) {
+			t.Parallel()
+			cron, err := ParseCron("0 0 30 2 *", nil)
+			assert.Nil(t, err)
+			_, ok := cron.Next(time.Now())
+			assert.False(t, ok)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Invalid spec", func(t *testing.T) {
+			t.Parallel()
+			for _, spec := range []string{
+				"",
+				"* * * *",
+				"* * * * * *",
+				"60 * * * *",
+				"* 24 * * *",
+				"* * 0 * *",
+				"* * * 13 *",
+				"* * * * 8",
+				"5-1 * * * *",
+				"*/0 * * * *",
+				"1,,2 * * * *",
+				"* * * foo *",
+				"@reboot",
+				"CRON_TZ=Mars/Olympus 0 0 * * *",
+			} {
+				_, err := ParseCron(spec, nil)
+				assert.True(t, errors.Is(err, ErrCronFormat), spec)
+			}
+		})
+	})
+}
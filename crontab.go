@@ -0,0 +1,353 @@
+package scheduler
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCrontabSource = "default"
+	defaultCrontabShell  = "/bin/sh"
+	crontabSourceTag     = "crontab:"
+	crontabUserTag       = "user:"
+	crontabMailtoTag     = "mailto:"
+	crontabReboot        = "@reboot"
+)
+
+type CrontabImportOption struct {
+	// User owns a per-user crontab, like the files of
+	// /var/spool/cron/crontabs, whose lines have no user field. An empty
+	// User reads a system crontab, like /etc/crontab, whose lines name their
+	// user after the schedule.
+	User string
+
+	// Source names the imported crontab, its jobs are tagged with
+	// "crontab:"+Source so importing it again cancels the removed lines.
+	Source string
+
+	// Location is the time zone of the lines before any CRON_TZ, default
+	// to the time zone of the scheduler.
+	Location *time.Location
+
+	// Reboot runs the @reboot lines once, they are skipped otherwise.
+	Reboot bool
+}
+
+type CrontabImportResult struct {
+	Added     []string
+	Unchanged []string
+	Cancelled []string
+	Skipped   []string
+}
+
+type crontabJob struct {
+	key     string
+	trigger Trigger
+	command *CommandJob
+	tags    []string
+}
+
+// crontabEnv is the environment set by the lines read so far.
+type crontabEnv struct {
+	vars     []string
+	shell    string
+	mailto   string
+	timeZone string
+	location *time.Location
+}
+
+func crontabError(line int, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrCrontabFormat, line, fmt.Sprintf(format, args...))
+}
+
+// parseCrontabEnv parses a "NAME=value" line, the value may be quoted.
+func parseCrontabEnv(text string) (name, value string, isEnv bool) {
+	name, value, isEnv = strings.Cut(text, "=")
+	name = strings.TrimSpace(name)
+	if !isEnv || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return name, value, true
+}
+
+// cutCrontabFields returns the first total fields of text and the rest of
+// it, left as written.
+func cutCrontabFields(text string, total int) (fields []string, rest string) {
+	rest = text
+	for len(fields) < total {
+		rest = strings.TrimLeft(rest, " \t")
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+
+		if end == 0 {
+			break
+		}
+		fields, rest = append(fields, rest[:end]), rest[end:]
+	}
+	return fields, strings.TrimSpace(rest)
+}
+
+// splitCrontabCommand splits command on its first unescaped percent sign,
+// the text after it is the standard input where the other unescaped percent
+// signs are new lines.
+func splitCrontabCommand(command string) (string, string) {
+	var (
+		parts   [2]strings.Builder
+		current = 0
+	)
+
+	for i := 0; i < len(command); i++ {
+		switch {
+		case command[i] == '\\' && i+1 < len(command) && command[i+1] == '%':
+			parts[current].WriteByte('%')
+			i++
+		case command[i] == '%' && current == 0:
+			current = 1
+		case command[i] == '%':
+			parts[current].WriteByte('\n')
+		default:
+			parts[current].WriteByte(command[i])
+		}
+	}
+
+	stdin := parts[1].String()
+	if current == 1 {
+		stdin += "\n"
+	}
+	return parts[0].String(), stdin
+}
+
+func (env *crontabEnv) set(line int, name, value string) error {
+	switch name {
+	case "CRON_TZ":
+		location, err := time.LoadLocation(value)
+		if err != nil {
+			return crontabError(line, "unknown time zone %q in CRON_TZ", value)
+		}
+		env.timeZone, env.location = value, location
+		return nil
+	case "SHELL":
+		env.shell = value
+	case "MAILTO":
+		env.mailto = value
+	}
+
+	env.vars = append(env.vars, name+"="+value)
+	return nil
+}
+
+// parseCrontabJob parses a job line like "*/5 * * * * command", with a user
+// field before the command when opt.User is empty.
+func parseCrontabJob(line int, text string, env *crontabEnv, opt *CrontabImportOption) (job *crontabJob, err error) {
+	total := 5
+	if strings.HasPrefix(text, "@") {
+		total = 1
+	}
+
+	user := opt.User
+	if user == "" {
+		total++
+	}
+
+	fields, command := cutCrontabFields(text, total)
+	switch {
+	case len(fields) < total && user == "":
+		return nil, crontabError(line, "%d fields instead of %d, a schedule and a user, before the command", len(fields), total)
+	case len(fields) < total:
+		return nil, crontabError(line, "%d fields instead of %d before the command", len(fields), total)
+	case command == "":
+		return nil, crontabError(line, "missing command")
+	case user == "":
+		user, fields = fields[total-1], fields[:total-1]
+	}
+
+	job = &crontabJob{tags: []string{crontabSourceTag + opt.Source, crontabUserTag + user}}
+	schedule := strings.Join(fields, " ")
+	if schedule != crontabReboot {
+		if env.timeZone != "" {
+			schedule = "CRON_TZ=" + env.timeZone + " " + schedule
+		}
+
+		if job.trigger, err = ParseCron(schedule, env.location); err != nil {
+			return nil, crontabError(line, "schedule %q: %v", strings.Join(fields, " "), err)
+		}
+	}
+
+	if env.mailto != "" {
+		job.tags = append(job.tags, crontabMailtoTag+env.mailto)
+	}
+
+	command, stdin := splitCrontabCommand(command)
+	job.command = &CommandJob{
+		Path:  env.shell,
+		Args:  []string{"-c", command},
+		Env:   append([]string(nil), env.vars...),
+		Stdin: stdin,
+	}
+
+	hash := sha256.New()
+	for _, value := range append([]string{user, schedule, env.shell, command, stdin}, job.command.Env...) {
+		_, _ = io.WriteString(hash, value+"\x00")
+	}
+	job.key = fmt.Sprintf("crontab:%s:%s:%x", opt.Source, user, hash.Sum(nil)[:6])
+	return
+}
+
+// parseCrontab returns the jobs of the crontab read from r, every invalid
+// line is reported with its line number.
+func parseCrontab(r io.Reader, opt *CrontabImportOption) (jobs []*crontabJob, err error) {
+	var (
+		scanner = bufio.NewScanner(r)
+		env     = &crontabEnv{shell: defaultCrontabShell, location: opt.Location}
+		keys    = make(map[string]struct{})
+		errs    []error
+		line    int
+	)
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if name, value, isEnv := parseCrontabEnv(text); isEnv {
+			if err := env.set(line, name, value); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		job, err := parseCrontabJob(line, text, env, opt)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// Identical lines collapse into one job, its key does not depend on
+		// which of them are removed.
+		if _, isExists := keys[job.key]; isExists {
+			continue
+		}
+		keys[job.key] = struct{}{}
+		jobs = append(jobs, job)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, errors.Join(errs...)
+}
+
+// ImportCrontab adds a CommandJob for every line of the crontab read from r,
+// run by SHELL, default to /bin/sh, with the environment set by the lines
+// before it. The key of a job is derived from the source, the user and the
+// content of its line, so a changed line is cancelled and added again under
+// a new key while the others are left untouched. Importing the same source
+// again cancels the removed lines. Identical lines, including their
+// environment, collapse into a single job run once per occurrence. The
+// commands run as the user of the scheduler, the user and MAILTO are only
+// kept as tags.
+//
+// Nothing is imported when a line is invalid, the error lists every invalid
+// line. Lines whose key belongs to another job and @reboot lines without
+// opt.Reboot are skipped. An @reboot line runs once for the lifetime of the
+// scheduler, importing it again reports it as unchanged.
+func (s *Scheduler) ImportCrontab(r io.Reader, opts ...CrontabImportOption) (res *CrontabImportResult, err error) {
+	opt := CrontabImportOption{}
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.Source == "" {
+		opt.Source = defaultCrontabSource
+	}
+
+	if opt.Location == nil {
+		opt.Location = s.locationTZ
+	}
+
+	jobs, err := parseCrontab(r, &opt)
+	if err != nil {
+		return
+	}
+
+	res = &CrontabImportResult{}
+	seen := make(map[string]struct{})
+	for _, job := range jobs {
+		seen[job.key] = struct{}{}
+		s.importCrontabJob(job, &opt, res)
+	}
+
+	sourced, err := s.Query(QueryOption{Tags: []string{crontabSourceTag + opt.Source}, Sort: QuerySortKey})
+	if err != nil {
+		return
+	}
+
+	for _, info := range sourced.Jobs {
+		if _, isExists := seen[info.Key]; isExists {
+			continue
+		}
+
+		if errCancel := s.Cancel(info.Key); errCancel == nil {
+			res.Cancelled = append(res.Cancelled, info.Key)
+		}
+	}
+	return
+}
+
+func (s *Scheduler) importCrontabJob(job *crontabJob, opt *CrontabImportOption, res *CrontabImportResult) {
+	if info, err := s.Get(job.key); err == nil {
+		if containsString(info.Tags, job.tags[0]) {
+			res.Unchanged = append(res.Unchanged, job.key)
+		} else {
+			res.Skipped = append(res.Skipped, job.key)
+		}
+		return
+	}
+
+	var err error
+	switch {
+	case job.trigger != nil:
+		err = s.AddTrigger(job.key, job.trigger, job.command.Run, JobOption{Tags: job.tags})
+	case opt.Reboot:
+		// An @reboot line runs once for the lifetime of the scheduler,
+		// importing it again after it ran leaves it as it is.
+		s.mutex.Lock()
+		_, isRebooted := s.crontabReboots[job.key]
+		s.crontabReboots[job.key] = struct{}{}
+		s.mutex.Unlock()
+
+		if isRebooted {
+			res.Unchanged = append(res.Unchanged, job.key)
+			return
+		}
+
+		if err = s.Add(job.key, 0, job.command.Run, JobOption{Tags: job.tags}); err != nil {
+			s.mutex.Lock()
+			delete(s.crontabReboots, job.key)
+			s.mutex.Unlock()
+		}
+	default:
+		err = ErrTriggerIsExhausted
+	}
+
+	if err != nil {
+		res.Skipped = append(res.Skipped, job.key)
+		return
+	}
+
+	res.Added = append(res.Added, job.key)
+}
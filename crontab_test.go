@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const systemCrontab = `# /etc/crontab
+SHELL=/bin/sh
+MAILTO="ops@example.com"
+
+CRON_TZ=Asia/Jakarta
+30 6 * * mon-fri  root    backup --full > /dev/null 2>&1
+@daily            www     cleanup %first line%second line
+@reboot           root    warmup
+`
+
+func TestImportCrontab(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("System crontab", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			res, err := schedule.ImportCrontab(strings.NewReader(systemCrontab), CrontabImportOption{Source: "etc"})
+			assert.Nil(t, err)
+			assert.Len(t, res.Added, 2)
+			assert.Len(t, res.Skipped, 1)
+			assert.True(t, strings.HasPrefix(res.Skipped[0], "crontab:etc:root:"))
+
+			jobs, err := schedule.Query(QueryOption{Tags: []string{"crontab:etc"}, Sort: QuerySortDateTime})
+			assert.Nil(t, err)
+			triggers := map[string]string{}
+			for _, info := range jobs.Jobs {
+				assert.Contains(t, info.Tags, "mailto:ops@example.com")
+				triggers[info.Tags[1]] = info.Trigger
+			}
+			assert.Equal(t, map[string]string{
+				"user:root": "CRON_TZ=Asia/Jakarta 30 6 * * mon-fri",
+				"user:www":  "CRON_TZ=Asia/Jakarta @daily",
+			}, triggers)
+
+			jobs, err = schedule.Query(QueryOption{Tags: []string{"user:root"}})
+			assert.Nil(t, err)
+			assert.Equal(t, 6, jobs.Jobs[0].DateTime.In(time.FixedZone("WIB", 7*3600)).Hour())
+		})
+
+		t.Run("Deterministic keys", func(t *testing.T) {
+			t.Parallel()
+			first, err := parseCrontab(strings.NewReader(systemCrontab), &CrontabImportOption{Source: "etc"})
+			assert.Nil(t, err)
+			second, err := parseCrontab(strings.NewReader("# moved\n\n"+systemCrontab), &CrontabImportOption{Source: "etc"})
+			assert.Nil(t, err)
+			for i := range first {
+				assert.Equal(t, first[i].key, second[i].key)
+			}
+
+			duplicated, err := parseCrontab(strings.NewReader("* * * * * a\n* * * * * b\n* * * * * a\n"), &CrontabImportOption{User: "alice"})
+			assert.Nil(t, err)
+			assert.Len(t, duplicated, 2)
+			single, err := parseCrontab(strings.NewReader("* * * * * b\n* * * * * a\n"), &CrontabImportOption{User: "alice"})
+			assert.Nil(t, err)
+			assert.Equal(t, duplicated[0].key, single[1].key)
+			assert.Equal(t, duplicated[1].key, single[0].key)
+		})
+
+		t.Run("Environment and standard input", func(t *testing.T) {
+			t.Parallel()
+			jobs, err := parseCrontab(strings.NewReader(systemCrontab), &CrontabImportOption{})
+			assert.Nil(t, err)
+			cleanup := jobs[1].command
+			assert.Equal(t, "/bin/sh", cleanup.Path)
+			assert.Equal(t, []string{"-c", "cleanup "}, cleanup.Args)
+			assert.Equal(t, "first line\nsecond line\n", cleanup.Stdin)
+			assert.Equal(t, []string{"SHELL=/bin/sh", "MAILTO=ops@example.com"}, cleanup.Env)
+
+			command, stdin := splitCrontabCommand(`date +\%Y`)
+			assert.Equal(t, "date +%Y", command)
+			assert.Empty(t, stdin)
+		})
+
+		t.Run("Import again", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			_, err := schedule.ImportCrontab(strings.NewReader("0 * * * * hourly\n0 0 * * * daily\n"), CrontabImportOption{User: "alice"})
+			assert.Nil(t, err)
+
+			res, err := schedule.ImportCrontab(strings.NewReader("0 * * * * hourly\n0 1 * * * daily\n"), CrontabImportOption{User: "alice"})
+			assert.Nil(t, err)
+			assert.Len(t, res.Unchanged, 1)
+			assert.Len(t, res.Added, 1)
+			assert.Len(t, res.Cancelled, 1)
+			assert.Equal(t, 2, schedule.Pending())
+		})
+
+		t.Run("Identical lines collapse", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			res, err := schedule.ImportCrontab(strings.NewReader("0 * * * * hourly\n0 * * * * hourly\n"), CrontabImportOption{User: "alice"})
+			assert.Nil(t, err)
+			assert.Len(t, res.Added, 1)
+
+			res, err = schedule.ImportCrontab(strings.NewReader("0 * * * * hourly\n"), CrontabImportOption{User: "alice"})
+			assert.Nil(t, err)
+			assert.Len(t, res.Unchanged, 1)
+			assert.Empty(t, res.Cancelled)
+			assert.Equal(t, 1, schedule.Pending())
+		})
+
+		t.Run("Run per-user line and reboot", func(t *testing.T) {
+			t.Parallel()
+			if runtime.GOOS == "windows" {
+				t.Skip("the commands are run with sh")
+			}
+
+			schedule := NewScheduler()
+			res, err := schedule.ImportCrontab(strings.NewReader("GREETING=hello\n@reboot echo \"$GREETING\" && cat%from stdin\n"), CrontabImportOption{User: "alice", Reboot: true})
+			assert.Nil(t, err)
+			assert.Len(t, res.Added, 1)
+			assert.Eventually(t, func() bool {
+				return len(schedule.History(res.Added[0])) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Equal(t, "hello\nfrom stdin\n", schedule.History(res.Added[0])[0].Output)
+
+			key := res.Added[0]
+			for i := 0; i < 2; i++ {
+				res, err = schedule.ImportCrontab(strings.NewReader("GREETING=hello\n@reboot echo \"$GREETING\" && cat%from stdin\n"), CrontabImportOption{User: "alice", Reboot: true})
+				assert.Nil(t, err)
+				assert.Equal(t, []string{key}, res.Unchanged)
+				assert.Empty(t, res.Added)
+			}
+			time.Sleep(50 * time.Millisecond)
+			assert.Len(t, schedule.History(key), 1)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Line numbered errors", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			_, err := schedule.ImportCrontab(strings.NewReader(strings.Join([]string{
+				"# comment",
+				"CRON_TZ=Mars/Olympus",
+				"61 * * * * root task",
+				"* * * * root",
+				"@hourly root",
+				"@fortnightly root task",
+				"0 0 * * * root ok",
+			}, "\n")))
+			assert.ErrorIs(t, err, ErrCrontabFormat)
+			assert.ErrorContains(t, err, `line 2: unknown time zone "Mars/Olympus" in CRON_TZ`)
+			assert.ErrorContains(t, err, `line 3: schedule "61 * * * *": the cron format is invalid: minute "61" is not between 0 and 59`)
+			assert.ErrorContains(t, err, "line 4: 5 fields instead of 6, a schedule and a user, before the command")
+			assert.ErrorContains(t, err, "line 5: missing command")
+			assert.ErrorContains(t, err, `line 6: schedule "@fortnightly": the cron format is invalid: unknown descriptor "@fortnightly"`)
+			assert.NotContains(t, err.Error(), "line 7")
+			assert.Equal(t, 0, schedule.Pending())
+		})
+
+		t.Run("Per-user line without command", func(t *testing.T) {
+			t.Parallel()
+			_, err := parseCrontab(strings.NewReader("\n* * * *\n"), &CrontabImportOption{User: "alice"})
+			assert.ErrorContains(t, err, "line 2: 4 fields instead of 5 before the command")
+		})
+
+		t.Run("Key of another job", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			jobs, err := parseCrontab(strings.NewReader("0 * * * * hourly\n"), &CrontabImportOption{User: "alice", Source: defaultCrontabSource})
+			assert.Nil(t, err)
+			assert.Nil(t, schedule.Add(jobs[0].key, time.Hour, fn))
+
+			res, err := schedule.ImportCrontab(strings.NewReader("0 * * * * hourly\n"), CrontabImportOption{User: "alice"})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{jobs[0].key}, res.Skipped)
+		})
+	})
+}
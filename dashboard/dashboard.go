@@ -0,0 +1,49 @@
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/sodri126/go-simple-scheduler/adminhandler"
+)
+
+const (
+	contentSecurityPolicy = "default-src 'self'; frame-ancestors 'none'"
+)
+
+//go:embed static
+var static embed.FS
+
+type Config struct {
+	// Admin configures the admin API called by the dashboard, its
+	// authenticators guard the dashboard as well.
+	Admin adminhandler.Config
+}
+
+// NewHandler serves the dashboard of s at / and the admin API it calls at
+// /api/. The pages only use relative URLs, so the handler can be mounted
+// under a prefix with http.StripPrefix. Every asset is embedded, the
+// dashboard loads nothing from other origins.
+func NewHandler(s *scheduler.Scheduler, configs ...Config) http.Handler {
+	config := Config{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	assets := http.FileServer(http.FS(files))
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", adminhandler.NewHandler(s, config.Admin)))
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		assets.ServeHTTP(w, r)
+	}))
+	return mux
+}
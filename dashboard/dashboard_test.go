@@ -0,0 +1,111 @@
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/sodri126/go-simple-scheduler/adminhandler"
+	"github.com/stretchr/testify/assert"
+)
+
+var fn = func(ctx context.Context) {}
+
+func newServer(t *testing.T, configs ...Config) (*scheduler.Scheduler, *httptest.Server) {
+	schedule := scheduler.NewScheduler()
+	server := httptest.NewServer(http.StripPrefix("/dashboard", NewHandler(schedule, configs...)))
+	t.Cleanup(server.Close)
+	return schedule, server
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Serve embedded assets", func(t *testing.T) {
+			t.Parallel()
+			_, server := newServer(t)
+
+			for path, contentType := range map[string]string{
+				"/dashboard/":          "text/html",
+				"/dashboard/app.js":    "javascript",
+				"/dashboard/style.css": "text/css",
+			} {
+				res, err := server.Client().Get(server.URL + path)
+				assert.Nil(t, err)
+				res.Body.Close()
+				assert.Equal(t, http.StatusOK, res.StatusCode, path)
+				assert.Contains(t, res.Header.Get("Content-Type"), contentType, path)
+				assert.Equal(t, contentSecurityPolicy, res.Header.Get("Content-Security-Policy"))
+			}
+		})
+
+		t.Run("Assets load nothing from other origins", func(t *testing.T) {
+			t.Parallel()
+			err := fs.WalkDir(static, "static", func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+
+				data, err := static.ReadFile(path)
+				assert.Nil(t, err)
+				assert.NotContains(t, string(data), "http://", path)
+				assert.NotContains(t, string(data), "https://", path)
+				assert.NotContains(t, string(data), "//cdn", path)
+				return nil
+			})
+			assert.Nil(t, err)
+		})
+
+		t.Run("Serve admin API and events", func(t *testing.T) {
+			t.Parallel()
+			schedule, server := newServer(t)
+			assert.Nil(t, schedule.Add("billing/1", time.Hour, fn))
+
+			res, err := server.Client().Get(server.URL + "/dashboard/api/jobs/billing%2F1")
+			assert.Nil(t, err)
+			data, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Contains(t, string(data), `"key":"billing/1"`)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/dashboard/api/events", nil)
+			assert.Nil(t, err)
+			res, err = server.Client().Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Nil(t, schedule.Cancel("billing/1"))
+			line, err := bufio.NewReader(res.Body).ReadString('\n')
+			assert.Nil(t, err)
+			assert.Equal(t, "event: cancelled\n", line)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Admin API requires credentials", func(t *testing.T) {
+			t.Parallel()
+			_, server := newServer(t, Config{Admin: adminhandler.Config{
+				Authenticators: []adminhandler.Authenticator{adminhandler.NewTokenAuthenticator(nil)},
+			}})
+
+			res, err := server.Client().Get(server.URL + "/dashboard/api/jobs")
+			assert.Nil(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+			res, err = server.Client().Get(server.URL + "/dashboard/")
+			assert.Nil(t, err)
+			body, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			assert.True(t, strings.Contains(string(body), "app.js"))
+		})
+	})
+}
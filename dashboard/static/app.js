@@ -0,0 +1,322 @@
+"use strict";
+
+// The dashboard reads the admin API served next to it under api/. The event
+// stream is read with fetch instead of EventSource so the bearer token can be
+// sent in the Authorization header.
+(function () {
+	const tokenKey = "scheduler-token";
+	const refreshDelay = 250;
+	const reconnectDelay = 2000;
+	const activitySize = 200;
+
+	const state = {
+		jobs: [],
+		history: [],
+		refreshTimer: null,
+	};
+
+	const $ = (id) => document.getElementById(id);
+
+	function headers() {
+		const token = sessionStorage.getItem(tokenKey);
+		return token ? { Authorization: "Bearer " + token } : {};
+	}
+
+	// The admin API only serves the mutations sent as application/json, even
+	// those without a body.
+	async function api(path, options = {}) {
+		const method = options.method || "GET";
+		const contentType = method === "GET" ? {} : { "Content-Type": "application/json" };
+		const res = await fetch("api/" + path, {
+			...options,
+			headers: { ...headers(), ...contentType, ...(options.headers || {}) },
+		});
+
+		if (!res.ok) {
+			let message = res.status + " " + res.statusText;
+			try {
+				message = (await res.json()).error || message;
+			} catch (e) {
+				// The body is not a JSON error.
+			}
+			throw new Error(message);
+		}
+		return res;
+	}
+
+	function showError(err) {
+		const el = $("error");
+		el.hidden = !err;
+		el.textContent = err ? err.message : "";
+	}
+
+	function element(tag, props = {}, ...children) {
+		const el = document.createElement(tag);
+		Object.assign(el, props);
+		for (const child of children) {
+			el.append(child);
+		}
+		return el;
+	}
+
+	function formatTime(value) {
+		if (!value) {
+			return "";
+		}
+		return new Date(value).toLocaleString();
+	}
+
+	function formatDuration(ms) {
+		if (ms < 1000) {
+			return ms + " ms";
+		}
+		return (ms / 1000).toFixed(1) + " s";
+	}
+
+	function escapeKey(key) {
+		return encodeURIComponent(key);
+	}
+
+	async function action(job, name) {
+		if (name === "cancel" && !confirm("Cancel " + job.key + "?")) {
+			return;
+		}
+
+		const path = "jobs/" + escapeKey(job.key);
+		try {
+			if (name === "cancel") {
+				await api(path, { method: "DELETE" });
+			} else {
+				await api(path + "/" + name, { method: "POST" });
+			}
+			showError(null);
+		} catch (err) {
+			showError(err);
+		}
+		scheduleRefresh();
+	}
+
+	function actionButton(job, name, label, className) {
+		return element("button", {
+			type: "button",
+			textContent: label,
+			className: className || "",
+			onclick: () => action(job, name),
+		});
+	}
+
+	function renderTimeline() {
+		const range = Number($("range").value);
+		const now = Date.now();
+		const timeline = $("timeline");
+		const axis = $("timeline-axis");
+		timeline.replaceChildren();
+		axis.replaceChildren();
+
+		for (const job of state.jobs) {
+			const at = new Date(job.date_time).getTime();
+			if (job.status === "running" || at < now || at > now + range) {
+				continue;
+			}
+
+			const marker = element("div", {
+				className: "timeline-marker " + job.status,
+				title: job.key + " at " + formatTime(job.date_time),
+			});
+			marker.style.left = ((at - now) / range * 100) + "%";
+			timeline.append(marker);
+		}
+
+		for (let i = 0; i <= 4; i++) {
+			axis.append(element("span", { textContent: formatTime(now + range * i / 4) }));
+		}
+	}
+
+	function renderRunning() {
+		const running = state.jobs.filter((job) => job.status === "running");
+		$("running-count").textContent = running.length;
+		$("running").replaceChildren(...running.map((job) => element("tr", {},
+			element("td", { className: "key", textContent: job.key }),
+			element("td", { textContent: job.handler || "" }),
+			element("td", { textContent: formatTime(job.date_time) }),
+			element("td", { textContent: job.run_count }),
+		)));
+	}
+
+	function renderUpcoming() {
+		const prefix = $("filter").value;
+		const upcoming = state.jobs.filter((job) => job.status !== "running" && job.key.startsWith(prefix));
+		$("upcoming-count").textContent = upcoming.length;
+		$("upcoming").replaceChildren(...upcoming.map((job) => {
+			const paused = job.status === "paused";
+			return element("tr", {},
+				element("td", { className: "key", textContent: job.key }),
+				element("td", { textContent: job.handler || "" }),
+				element("td", { textContent: formatTime(job.date_time) }),
+				element("td", { className: "status-" + job.status, textContent: job.status }),
+				element("td", { textContent: job.trigger.split("\n").pop() }),
+				element("td", {}, ...(job.tags || []).map((tag) => element("span", { className: "tag", textContent: tag }))),
+				element("td", { className: "actions" },
+					actionButton(job, "run", "Run now"),
+					" ",
+					paused ? actionButton(job, "resume", "Resume") : actionButton(job, "pause", "Pause"),
+					" ",
+					actionButton(job, "cancel", "Cancel", "danger"),
+				),
+			);
+		}));
+	}
+
+	function output(text) {
+		return element("details", {},
+			element("summary", { textContent: "Output" }),
+			element("pre", { className: "output", textContent: text }),
+		);
+	}
+
+	function renderHistory() {
+		const failuresOnly = $("failures-only").checked;
+		const runs = [];
+		for (const history of state.history) {
+			for (const run of history.runs) {
+				if (!failuresOnly || run.outcome === "failure") {
+					runs.push({ key: history.key, ...run });
+				}
+			}
+		}
+
+		runs.sort((a, b) => new Date(b.end_time || b.date_time) - new Date(a.end_time || a.date_time));
+		$("history").replaceChildren(...runs.map((run) => element("tr", { className: run.outcome },
+			element("td", { className: "key", textContent: run.key }),
+			element("td", { textContent: formatTime(run.date_time) }),
+			element("td", { textContent: formatTime(run.start_time) }),
+			element("td", { textContent: formatDuration(new Date(run.end_time) - new Date(run.start_time)) }),
+			element("td", { textContent: run.attempt }),
+			element("td", { textContent: run.outcome }),
+			element("td", {}, run.error || "", run.output ? output(run.output) : ""),
+		)));
+	}
+
+	function render() {
+		renderTimeline();
+		renderRunning();
+		renderUpcoming();
+		renderHistory();
+	}
+
+	async function refresh() {
+		state.refreshTimer = null;
+		try {
+			const [jobs, history] = await Promise.all([
+				api("jobs?format=detail&sort=date_time").then((res) => res.json()),
+				api("history").then((res) => res.json()),
+			]);
+			state.jobs = jobs;
+			state.history = history;
+			showError(null);
+		} catch (err) {
+			showError(err);
+		}
+		render();
+	}
+
+	function scheduleRefresh() {
+		if (state.refreshTimer === null) {
+			state.refreshTimer = setTimeout(refresh, refreshDelay);
+		}
+	}
+
+	function addActivity(name, event) {
+		let text = formatTime(new Date()) + " " + name + " " + event.key;
+		if (event.error) {
+			text += ": " + event.error;
+		}
+
+		const activity = $("activity");
+		activity.prepend(element("li", { className: name, textContent: text }));
+		while (activity.children.length > activitySize) {
+			activity.lastChild.remove();
+		}
+	}
+
+	function setConnection(isLive) {
+		const el = $("connection");
+		el.classList.toggle("live", isLive);
+		el.textContent = isLive ? "live" : "reconnecting";
+	}
+
+	// dispatch parses one server-sent event, comments only keep the stream
+	// open.
+	function dispatch(block) {
+		let name = "message";
+		let data = "";
+		for (const line of block.split("\n")) {
+			if (line.startsWith("event: ")) {
+				name = line.slice(7);
+			} else if (line.startsWith("data: ")) {
+				data += line.slice(6);
+			}
+		}
+
+		if (!data) {
+			return;
+		}
+
+		addActivity(name, JSON.parse(data));
+		scheduleRefresh();
+	}
+
+	async function watch() {
+		for (;;) {
+			try {
+				const res = await api("events");
+				const reader = res.body.pipeThrough(new TextDecoderStream()).getReader();
+				setConnection(true);
+				scheduleRefresh();
+
+				let buffer = "";
+				for (;;) {
+					const { value, done } = await reader.read();
+					if (done) {
+						break;
+					}
+
+					buffer += value;
+					let end;
+					while ((end = buffer.indexOf("\n\n")) >= 0) {
+						dispatch(buffer.slice(0, end));
+						buffer = buffer.slice(end + 2);
+					}
+				}
+			} catch (err) {
+				showError(err);
+			}
+
+			setConnection(false);
+			await new Promise((resolve) => setTimeout(resolve, reconnectDelay));
+		}
+	}
+
+	function signIn() {
+		const token = prompt("Bearer token, empty to sign out", sessionStorage.getItem(tokenKey) || "");
+		if (token === null) {
+			return;
+		}
+
+		if (token) {
+			sessionStorage.setItem(tokenKey, token);
+		} else {
+			sessionStorage.removeItem(tokenKey);
+		}
+		location.reload();
+	}
+
+	$("sign-in").addEventListener("click", signIn);
+	$("range").addEventListener("change", renderTimeline);
+	$("filter").addEventListener("input", renderUpcoming);
+	$("failures-only").addEventListener("change", renderHistory);
+	setInterval(renderTimeline, 30000);
+
+	refresh();
+	watch();
+})();
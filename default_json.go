@@ -2,32 +2,67 @@ package scheduler
 
 import (
 	"encoding/json"
-	"time"
+	"io"
 )
 
+// NewJsonResponse renders every job as an object of its key and date time.
 func NewJsonResponse() ListConverter {
-	return &jsonResponse{}
+	return &jsonResponse{config: ListConfig{Columns: jsonListColumns}}
 }
 
 type jsonResponse struct {
+	config ListConfig
 }
 
-type jsonData struct {
-	Key      string    `json:"key"`
-	DateTime time.Time `json:"date_time"`
+func (d *jsonResponse) Convert(data []*ResponseScheduler) ([]byte, error) {
+	return convertStream(d, data)
 }
 
-func (d *jsonResponse) transformToJsonData(responses []*ResponseScheduler) (data []*jsonData) {
-	for i := 0; i < len(responses); i++ {
-		data = append(data, &jsonData{
-			Key:      responses[i].Key,
-			DateTime: responses[i].Time,
-		})
+func (d *jsonResponse) Stream(w io.Writer, next ResponseIterator) (n int, err error) {
+	cw := &countWriter{w: w}
+	cw.WriteString("[")
+	for i := 0; cw.err == nil; i++ {
+		res, ok := next()
+		if !ok {
+			break
+		}
+
+		var item []byte
+		if item, err = d.item(i+1, res); err != nil {
+			return cw.n, err
+		}
+
+		if i > 0 {
+			cw.WriteString(",")
+		}
+		_, _ = cw.Write(item)
 	}
-	return
+	cw.WriteString("]")
+	return cw.n, cw.err
 }
 
-func (d *jsonResponse) Convert(data []*ResponseScheduler) ([]byte, error) {
-	transformData := d.transformToJsonData(data)
-	return json.Marshal(transformData)
+// item renders the columns of data as an object keeping their order.
+func (d *jsonResponse) item(no int, data *ResponseScheduler) (res []byte, err error) {
+	res = append(res, '{')
+	for i, column := range d.config.Columns {
+		var value interface{} = column.value(no, data, d.config.TimeFormat)
+		if column == ListColumnTags {
+			value = data.Tags
+		}
+
+		var field, val []byte
+		if field, err = json.Marshal(listFields[column]); err != nil {
+			return
+		}
+
+		if val, err = json.Marshal(value); err != nil {
+			return
+		}
+
+		if i > 0 {
+			res = append(res, ',')
+		}
+		res = append(append(append(res, field...), ':'), val...)
+	}
+	return append(res, '}'), nil
 }
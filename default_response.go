@@ -1,24 +1,99 @@
 package scheduler
 
 import (
+	"io"
+	"strings"
+
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
+// NewDefaultResponse renders a table.
 func NewDefaultResponse() ListConverter {
-	return &defaultResponse{
-		tableWriter: table.NewWriter(),
-	}
+	return &defaultResponse{config: ListConfig{Columns: defaultListColumns}}
 }
 
 type defaultResponse struct {
-	tableWriter table.Writer
+	config ListConfig
 }
 
 func (d *defaultResponse) Convert(data []*ResponseScheduler) (res []byte, err error) {
-	d.tableWriter.AppendHeader(table.Row{"No.", "Key", "Date Time"})
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(d.header())
 	for i := 0; i < len(data); i++ {
-		d.tableWriter.AppendRow(table.Row{i + 1, data[i].Key, data[i].Time})
+		tableWriter.AppendRow(d.row(i+1, data[i]))
+	}
+	res = []byte(tableWriter.Render())
+	return
+}
+
+func (d *defaultResponse) header() table.Row {
+	header := make(table.Row, len(d.config.Columns))
+	for i, column := range d.config.Columns {
+		header[i] = string(column)
+	}
+	return header
+}
+
+func (d *defaultResponse) row(no int, data *ResponseScheduler) table.Row {
+	row := make(table.Row, len(d.config.Columns))
+	for i, column := range d.config.Columns {
+		row[i] = column.value(no, data, d.config.TimeFormat)
+	}
+	return row
+}
+
+// Stream renders the rows in batches of listBatchSize without keeping the
+// whole list in memory. The column widths of a batch are those of the
+// previous batches widened to fit its own rows, so a cell wider than every
+// cell before it misaligns the rows after it. Convert renders a single
+// table.
+func (d *defaultResponse) Stream(w io.Writer, next ResponseIterator) (n int, err error) {
+	cw := &countWriter{w: w}
+	var (
+		widths []int
+		no     int
+	)
+
+	res, ok := next()
+	for isFirst := true; cw.err == nil && (isFirst || ok); isFirst = false {
+		tableWriter := table.NewWriter()
+		if isFirst {
+			tableWriter.AppendHeader(d.header())
+		}
+
+		for i := 0; ok && i < listBatchSize; i++ {
+			no++
+			tableWriter.AppendRow(d.row(no, res))
+			res, ok = next()
+		}
+
+		configs := make([]table.ColumnConfig, len(widths))
+		for i, width := range widths {
+			configs[i] = table.ColumnConfig{Number: i + 1, WidthMin: width}
+		}
+		tableWriter.SetColumnConfigs(configs)
+
+		// Only the first batch draws the top border and only the last one
+		// the bottom border, so the batches render a single table.
+		lines := strings.Split(tableWriter.Render(), "\n")
+		widths = borderWidths(lines[0])
+		if !isFirst {
+			lines[0] = ""
+		}
+
+		if ok {
+			lines = lines[:len(lines)-1]
+		}
+		cw.WriteString(strings.Join(lines, "\n"))
+	}
+	return cw.n, cw.err
+}
+
+// borderWidths returns the width of every column of a table border like
+// "+-----+-----+", without the padding.
+func borderWidths(border string) (widths []int) {
+	for _, column := range strings.Split(strings.Trim(border, "+"), "+") {
+		widths = append(widths, len(column)-2)
 	}
-	res = []byte(d.tableWriter.Render())
 	return
 }
@@ -1,32 +1,122 @@
 package scheduler
 
 import (
+	"math/rand"
 	"time"
 )
 
 type detailScheduler struct {
-	key      string
-	idx      int
-	timer    *time.Timer
-	dateTime time.Time
+	key       string
+	timer     *time.Timer
+	fn        FnScheduler
+	dateTime  time.Time
+	handler   string
+	paused    bool
+	remaining time.Duration
+	running   bool
+	runs      int
+	tags      []string
+	trigger   Trigger
+
+	// pauseOnRearm is set when a recurring job is paused after its timer
+	// fired, rearm stops it at its next date time.
+	pauseOnRearm bool
+}
+
+func (ds *detailScheduler) toEvent() Event {
+	return Event{
+		Key:      ds.key,
+		Handler:  ds.handler,
+		DateTime: ds.dateTime,
+	}
+}
+
+// detailSchedulersMaxLevel bounds the levels of the skip list, enough for
+// 4^16 jobs.
+const detailSchedulersMaxLevel = 16
+
+// detailSchedulers orders the jobs by date time and key in a skip list, so
+// the jobs following a cursor are found without scanning every job. The
+// date time of an added job is only changed through Move.
+type detailSchedulers struct {
+	head [detailSchedulersMaxLevel]*detailSchedulersNode
+	rand *rand.Rand
+}
+
+type detailSchedulersNode struct {
+	ds   *detailScheduler
+	next []*detailSchedulersNode
 }
 
-type detailSchedulers []*detailScheduler
+// before reports whether ds comes before dateTime and key.
+func (ds *detailScheduler) before(dateTime time.Time, key string) bool {
+	if !ds.dateTime.Equal(dateTime) {
+		return ds.dateTime.Before(dateTime)
+	}
 
-func (dss *detailSchedulers) Remove(index int) {
-	dsSlice := *dss
-	dsSlice = append(dsSlice[:index], dsSlice[index+1:]...)
+	return ds.key < key
+}
+
+// path returns the next pointers of every level leading to dateTime and
+// key, the pointer of level i is the element i of path[i].
+func (dss *detailSchedulers) path(dateTime time.Time, key string) (path [detailSchedulersMaxLevel][]*detailSchedulersNode) {
+	next := dss.head[:]
+	for level := detailSchedulersMaxLevel - 1; level >= 0; level-- {
+		for next[level] != nil && next[level].ds.before(dateTime, key) {
+			next = next[level].next
+		}
+		path[level] = next
+	}
+	return
 }
 
 func (dss *detailSchedulers) Add(ds *detailScheduler) {
-	*dss = append(*dss, ds)
+	if dss.rand == nil {
+		dss.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	level := 1
+	for level < detailSchedulersMaxLevel && dss.rand.Intn(4) == 0 {
+		level++
+	}
+
+	path := dss.path(ds.dateTime, ds.key)
+	node := &detailSchedulersNode{ds: ds, next: make([]*detailSchedulersNode, level)}
+	for i := 0; i < level; i++ {
+		node.next[i] = path[i][i]
+		path[i][i] = node
+	}
+}
+
+func (dss *detailSchedulers) Remove(ds *detailScheduler) {
+	path := dss.path(ds.dateTime, ds.key)
+	node := path[0][0]
+	if node == nil || node.ds != ds {
+		return
+	}
+
+	for i := range node.next {
+		path[i][i] = node.next[i]
+	}
 }
 
-func (dss *detailSchedulers) Replace(index int, ds *detailScheduler) {
-	dssSlice := *dss
-	dssSlice[index] = ds
+// Move changes the date time of ds and moves it to its new place.
+func (dss *detailSchedulers) Move(ds *detailScheduler, dateTime time.Time) {
+	dss.Remove(ds)
+	ds.dateTime = dateTime
+	dss.Add(ds)
 }
 
-func (dss *detailSchedulers) Total() int {
-	return len(*dss)
+// After returns at most limit jobs following dateTime and key, limit 0
+// returns every job. A zero dateTime and an empty key start at the first
+// job.
+func (dss *detailSchedulers) After(dateTime time.Time, key string, limit int) (res []*detailScheduler) {
+	node := dss.path(dateTime, key)[0][0]
+	for ; node != nil && (limit == 0 || len(res) < limit); node = node.next[0] {
+		if node.ds.dateTime.Equal(dateTime) && node.ds.key == key {
+			continue
+		}
+		res = append(res, node.ds)
+	}
+	return
 }
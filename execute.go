@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type executionKey struct{}
+
+type Middleware func(next FnScheduler) FnScheduler
+
+type Execution struct {
+	Key       string
+	Handler   string
+	DateTime  time.Time
+	StartTime time.Time
+	Attempt   int
+}
+
+type execution struct {
+	Execution
+	err    error
+	output string
+	mutex  sync.Mutex
+}
+
+func ExecutionFromContext(ctx context.Context) (exec Execution, isExists bool) {
+	e, isExists := ctx.Value(executionKey{}).(*execution)
+	if isExists {
+		exec = e.Execution
+	}
+	return
+}
+
+func ErrFromContext(ctx context.Context) error {
+	e, isExists := ctx.Value(executionKey{}).(*execution)
+	if !isExists {
+		return nil
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.err
+}
+
+// Fail marks the running job as failed with err, the job keeps running
+// until its function returns.
+func Fail(ctx context.Context, err error) {
+	e, isExists := ctx.Value(executionKey{}).(*execution)
+	if !isExists || err == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.err = err
+}
+
+// SetOutput records output in the run history of the running job, it
+// replaces the output set before.
+func SetOutput(ctx context.Context, output string) {
+	e, isExists := ctx.Value(executionKey{}).(*execution)
+	if !isExists {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.output = output
+}
+
+func run(ctx context.Context, event Event, fn FnScheduler, middlewares []Middleware) (output string, err error) {
+	e := &execution{
+		Execution: Execution{
+			Key:       event.Key,
+			Handler:   event.Handler,
+			DateTime:  event.DateTime,
+			StartTime: event.StartTime,
+			Attempt:   event.Attempt,
+		},
+	}
+	defer func() {
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+
+		output, err = e.output, e.err
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrJobPanic, r)
+		}
+	}()
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		fn = middlewares[i](fn)
+	}
+
+	fn(context.WithValue(ctx, executionKey{}, e))
+	return
+}
+
+func (s *Scheduler) execute(event Event, fn FnScheduler) (isExecuted bool, err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if s.config.ExecutionLocker != nil {
+		name := fmt.Sprintf("%s@%d", event.Key, event.DateTime.UnixNano())
+		isLocked, errLock := s.config.ExecutionLocker.Lock(ctx, name, s.owner, s.config.ExecutionLockTTL)
+		if errLock != nil || !isLocked {
+			event.Err = ErrExecutionIsLocked
+			if errLock != nil {
+				event.Err = fmt.Errorf("%w: %v", ErrExecutionIsLocked, errLock)
+			}
+
+			s.emit(EventTypeSkipped, event)
+			return
+		}
+
+		if s.config.ExecutionLockRenew {
+			go s.renewExecutionLock(ctx, name)
+		}
+	}
+
+	isExecuted = true
+	event.StartTime = time.Now()
+	s.emit(EventTypeStarted, event)
+
+	s.mutex.Lock()
+	s.running++
+	id := s.track(event.Key, cancel)
+	middlewares := s.middlewares
+	s.mutex.Unlock()
+
+	event.Output, err = run(ctx, event, fn, middlewares)
+
+	s.mutex.Lock()
+	s.running--
+	s.untrack(event.Key, id)
+	s.mutex.Unlock()
+
+	event.EndTime, event.Err = time.Now(), err
+	if err != nil {
+		s.emit(EventTypeFailed, event)
+		return
+	}
+
+	s.emit(EventTypeSucceeded, event)
+	return
+}
+
+// track registers the cancel function of a running execution of key, or of
+// the wait before its retry, so Cancel interrupts it. s.mutex must be held.
+func (s *Scheduler) track(key string, cancel context.CancelFunc) (id uint64) {
+	s.executionID++
+	if s.executions[key] == nil {
+		s.executions[key] = make(map[uint64]context.CancelFunc)
+	}
+
+	s.executions[key][s.executionID] = cancel
+	return s.executionID
+}
+
+// untrack removes an execution registered by track, s.mutex must be held.
+func (s *Scheduler) untrack(key string, id uint64) {
+	delete(s.executions[key], id)
+	if len(s.executions[key]) == 0 {
+		delete(s.executions, key)
+	}
+}
+
+// interrupt cancels the context of the running executions of key.
+func (s *Scheduler) interrupt(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, cancel := range s.executions[key] {
+		cancel()
+	}
+}
+
+// RunNow runs the job of key once without waiting for its date time, the
+// job stays scheduled. The run is reported like a fire of the job.
+func (s *Scheduler) RunNow(key string) (err error) {
+	s.mutex.RLock()
+	ds, isExists := s.schedulers[key]
+	if !isExists {
+		s.mutex.RUnlock()
+		err = ErrKeyIsNotExists
+		return
+	}
+
+	event, fn := ds.toEvent(), ds.fn
+	s.mutex.RUnlock()
+
+	event.DateTime, event.Attempt = time.Now(), 1
+	go s.executeRetrying(event, fn)
+	return
+}
+
+func (s *Scheduler) Use(middleware Middleware) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.middlewares = append(s.middlewares, middleware)
+}
+
+func (s *Scheduler) renewExecutionLock(ctx context.Context, name string) {
+	ticker := time.NewTicker(s.config.ExecutionLockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.config.ExecutionLocker.Lock(ctx, name, s.owner, s.config.ExecutionLockTTL)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionLocker(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Occurrence runs once across schedulers", func(t *testing.T) {
+			t.Parallel()
+			var (
+				counter  int32
+				locker   = NewMemoryLocker()
+				dateTime = time.Now().UTC().Add(100 * time.Millisecond)
+			)
+
+			for i := 0; i < 3; i++ {
+				schedule := NewScheduler(Config{ExecutionLocker: locker})
+				for j := 1; j <= 100; j++ {
+					err := schedule.AddDate(fmt.Sprintf("add#%d", j), dateTime, func(ctx context.Context) {
+						atomic.AddInt32(&counter, 1)
+					})
+					assert.Nil(t, err)
+				}
+			}
+
+			time.Sleep(300 * time.Millisecond)
+			assert.Equal(t, int32(100), atomic.LoadInt32(&counter))
+		})
+
+		t.Run("Different occurrences of the same key run", func(t *testing.T) {
+			t.Parallel()
+			var (
+				counter int32
+				locker  = NewMemoryLocker()
+			)
+
+			schedule := NewScheduler(Config{ExecutionLocker: locker})
+			increment := func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			}
+			assert.Nil(t, schedule.Add("add#1", 50*time.Millisecond, increment))
+			time.Sleep(100 * time.Millisecond)
+			assert.Nil(t, schedule.Add("add#1", 50*time.Millisecond, increment))
+			time.Sleep(100 * time.Millisecond)
+			assert.Equal(t, int32(2), atomic.LoadInt32(&counter))
+		})
+
+		t.Run("Lock is renewed while the job is running", func(t *testing.T) {
+			t.Parallel()
+			var (
+				locker   = NewMemoryLocker()
+				dateTime = time.Now().UTC().Add(50 * time.Millisecond)
+				started  = make(chan struct{})
+				release  = make(chan struct{})
+			)
+
+			schedule := NewScheduler(Config{
+				ExecutionLocker:    locker,
+				ExecutionLockTTL:   60 * time.Millisecond,
+				ExecutionLockRenew: true,
+			})
+			err := schedule.AddDate("add#1", dateTime, func(ctx context.Context) {
+				close(started)
+				<-release
+			})
+			assert.Nil(t, err)
+
+			<-started
+			time.Sleep(200 * time.Millisecond)
+			name := fmt.Sprintf("add#1@%d", dateTime.UnixNano())
+			isLocked, err := locker.Lock(context.Background(), name, "other", time.Minute)
+			assert.Nil(t, err)
+			assert.False(t, isLocked)
+			close(release)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Skip when another scheduler holds the lock", func(t *testing.T) {
+			t.Parallel()
+			var (
+				counter  int32
+				locker   = NewMemoryLocker()
+				dateTime = time.Now().UTC().Add(50 * time.Millisecond)
+			)
+
+			name := fmt.Sprintf("add#1@%d", dateTime.UnixNano())
+			isLocked, err := locker.Lock(context.Background(), name, "other", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			schedule := NewScheduler(Config{ExecutionLocker: locker})
+			err = schedule.AddDate("add#1", dateTime, func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			})
+			assert.Nil(t, err)
+			time.Sleep(150 * time.Millisecond)
+			assert.Equal(t, int32(0), atomic.LoadInt32(&counter))
+		})
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Middlewares wrap the job in order", func(t *testing.T) {
+			t.Parallel()
+			var (
+				calls   []string
+				exec    Execution
+				errJob  error
+				done    = make(chan struct{})
+				failure = fmt.Errorf("failure")
+			)
+
+			middleware := func(name string) Middleware {
+				return func(next FnScheduler) FnScheduler {
+					return func(ctx context.Context) {
+						calls = append(calls, name)
+						next(ctx)
+						if name == "first" {
+							errJob = ErrFromContext(ctx)
+							close(done)
+						}
+					}
+				}
+			}
+
+			schedule := NewScheduler(Config{Middlewares: []Middleware{middleware("first")}})
+			schedule.Use(middleware("second"))
+			dateTime := time.Now().UTC().Add(20 * time.Millisecond)
+			err := schedule.AddDate("add#1", dateTime, func(ctx context.Context) {
+				calls = append(calls, "job")
+				exec, _ = ExecutionFromContext(ctx)
+				Fail(ctx, failure)
+			})
+			assert.Nil(t, err)
+
+			<-done
+			assert.Equal(t, []string{"first", "second", "job"}, calls)
+			assert.Equal(t, failure, errJob)
+			assert.Equal(t, "add#1", exec.Key)
+			assert.Equal(t, 1, exec.Attempt)
+			assert.True(t, exec.DateTime.Equal(dateTime))
+			assert.False(t, exec.StartTime.Before(dateTime))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Context without execution", func(t *testing.T) {
+			t.Parallel()
+			_, isExists := ExecutionFromContext(context.Background())
+			assert.False(t, isExists)
+			assert.Nil(t, ErrFromContext(context.Background()))
+			Fail(context.Background(), fmt.Errorf("failure"))
+		})
+	})
+}
+
+func TestRunNow(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Run key without changing its date time", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("add#1", time.Hour, func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			}))
+			before, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+
+			assert.Nil(t, schedule.RunNow("add#1"))
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&counter) == 1
+			}, time.Second, 5*time.Millisecond)
+
+			after, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, before.DateTime, after.DateTime)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Run unknown key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Equal(t, ErrKeyIsNotExists, schedule.RunNow("add#1"))
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+func (s *Scheduler) handler(name string) (fn FnScheduler, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	fn, isExists := s.handlers[name]
+	if !isExists {
+		err = ErrHandlerIsNotExists
+	}
+	return
+}
+
+func (s *Scheduler) addHandler(key string, param *paramScheduler) (err error) {
+	fn, err := s.handler(param.handler)
+	if err != nil {
+		return
+	}
+
+	if s.config.Store == nil {
+		return s.add(key, param, fn)
+	}
+
+	isExists, _ := s.read(key)
+	if isExists {
+		err = ErrKeyIsExists
+		return
+	}
+
+	job := &StoreJob{
+		Key:      key,
+		Handler:  param.handler,
+		DateTime: param.dateTime,
+	}
+
+	if err = s.config.Store.Add(context.Background(), job); err != nil {
+		return
+	}
+
+	event := job.toEvent()
+	s.emit(EventTypeScheduled, event)
+	return
+}
+
+func (s *Scheduler) RegisterHandler(name string, fn FnScheduler) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, isExists := s.handlers[name]; isExists {
+		err = ErrHandlerIsExists
+		return
+	}
+
+	s.handlers[name] = fn
+	return
+}
+
+func (s *Scheduler) AddHandler(key string, duration time.Duration, handler string, opts ...JobOption) (err error) {
+	return s.addHandler(key, &paramScheduler{
+		duration: duration,
+		dateTime: s.fromDurationToDateTime(duration),
+		handler:  handler,
+		tags:     jobTags(opts),
+	})
+}
+
+func (s *Scheduler) AddDateHandler(key string, dateTime time.Time, handler string, opts ...JobOption) (err error) {
+	duration, err := s.subtractDateTime(dateTime)
+	if err != nil {
+		return
+	}
+
+	return s.addHandler(key, &paramScheduler{
+		duration: duration,
+		dateTime: dateTime.In(s.locationTZ),
+		handler:  handler,
+		tags:     jobTags(opts),
+	})
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+func NewHistoryResponse() ListConverter {
+	return &historyResponse{}
+}
+
+type historyResponse struct {
+}
+
+func (d *historyResponse) Convert(data []*ResponseScheduler) (res []byte, err error) {
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{"No.", "Key", "Date Time", "Start Time", "End Time", "Outcome", "Error"})
+	no := 0
+	for i := 0; i < len(data); i++ {
+		for _, run := range data[i].History {
+			no++
+			errMsg := ""
+			if run.Err != nil {
+				errMsg = run.Err.Error()
+			}
+
+			tableWriter.AppendRow(table.Row{no, data[i].Key, run.DateTime, run.StartTime, run.EndTime, run.Outcome, errMsg})
+		}
+	}
+	res = []byte(tableWriter.Render())
+	return
+}
@@ -0,0 +1,485 @@
+package scheduler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultICalSource = "default"
+	icalSourceTag     = "ical:"
+)
+
+type ICalImportOption struct {
+	// Handler is bound to the events without a X-SCHEDULER-HANDLER property.
+	Handler string
+
+	// Source names the imported calendar, its jobs are tagged with
+	// "ical:"+Source so importing it again cancels the removed events.
+	Source string
+}
+
+type ICalImportResult struct {
+	Added     []string
+	Replaced  []string
+	Unchanged []string
+	Cancelled []string
+	Skipped   []string
+}
+
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+	line   int
+}
+
+type icalComponent struct {
+	name       string
+	properties []*icalProperty
+	components []*icalComponent
+	line       int
+}
+
+type icalJob struct {
+	key     string
+	handler string
+	trigger *RRule
+	tags    []string
+}
+
+func (c *icalComponent) property(name string) *icalProperty {
+	for _, prop := range c.properties {
+		if prop.name == name {
+			return prop
+		}
+	}
+	return nil
+}
+
+func (c *icalComponent) all(name string) (res []*icalProperty) {
+	for _, prop := range c.properties {
+		if prop.name == name {
+			res = append(res, prop)
+		}
+	}
+	return
+}
+
+func (c *icalComponent) children(name string) (res []*icalComponent) {
+	for _, component := range c.components {
+		if component.name == name {
+			res = append(res, component)
+		}
+	}
+	return
+}
+
+func icalError(line int, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrICalFormat, line, fmt.Sprintf(format, args...))
+}
+
+// parseICal returns the VCALENDAR of r, folded lines are unfolded and the
+// line numbers of the properties are the line of their first fold.
+func parseICal(r io.Reader) (root *icalComponent, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		stack   []*icalComponent
+		logical string
+		start   int
+	)
+
+	flush := func() error {
+		if logical == "" {
+			return nil
+		}
+
+		prop, err := parseICalProperty(logical, start)
+		if err != nil {
+			return err
+		}
+		logical = ""
+
+		switch prop.name {
+		case "BEGIN":
+			component := &icalComponent{name: strings.ToUpper(prop.value), line: prop.line}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.components = append(parent.components, component)
+			} else if root == nil {
+				root = component
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.ToUpper(prop.value) {
+				return icalError(prop.line, "unexpected END:%s", prop.value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return icalError(prop.line, "property %s outside of a component", prop.name)
+			}
+			component := stack[len(stack)-1]
+			component.properties = append(component.properties, prop)
+		}
+		return nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			logical += text[1:]
+			continue
+		}
+
+		if err = flush(); err != nil {
+			return
+		}
+		logical, start = text, line
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrICalFormat, err)
+	}
+
+	if err = flush(); err != nil {
+		return
+	}
+
+	if root == nil || root.name != "VCALENDAR" {
+		return nil, fmt.Errorf("%w: VCALENDAR is required", ErrICalFormat)
+	}
+
+	if len(stack) > 0 {
+		return nil, icalError(stack[len(stack)-1].line, "%s is not ended", stack[len(stack)-1].name)
+	}
+	return
+}
+
+func parseICalProperty(text string, line int) (prop *icalProperty, err error) {
+	prop = &icalProperty{params: make(map[string]string), line: line}
+	isQuoted, begin, name := false, 0, ""
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '"':
+			isQuoted = !isQuoted
+		case isQuoted:
+		case c == ';' || c == ':':
+			part := text[begin:i]
+			if name == "" {
+				name = part
+			} else {
+				key, value, _ := strings.Cut(part, "=")
+				prop.params[strings.ToUpper(key)] = strings.Trim(value, "\"")
+			}
+
+			begin = i + 1
+			if c == ':' {
+				prop.name, prop.value = strings.ToUpper(name), text[i+1:]
+				return
+			}
+		}
+	}
+
+	return nil, icalError(line, "invalid content line %q", text)
+}
+
+func unescapeICalText(text string) string {
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			sb.WriteByte(text[i])
+			continue
+		}
+
+		i++
+		switch text[i] {
+		case 'n', 'N':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(text[i])
+		}
+	}
+	return sb.String()
+}
+
+// splitICalText splits the escaped list text on its unescaped commas.
+func splitICalText(text string) (res []string) {
+	begin := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case ',':
+			res = append(res, unescapeICalText(text[begin:i]))
+			begin = i + 1
+		}
+	}
+	return append(res, unescapeICalText(text[begin:]))
+}
+
+// icalLocations returns the time zones of the VTIMEZONE of root. A TZID
+// unknown to the time zone database, like the Windows names of Outlook, is
+// built from the offsets and the rules of its observances.
+func icalLocations(root *icalComponent) (locations map[string]*time.Location, err error) {
+	locations = make(map[string]*time.Location)
+	for _, tz := range root.children("VTIMEZONE") {
+		tzid := tz.property("TZID")
+		if tzid == nil {
+			return nil, icalError(tz.line, "TZID is required")
+		}
+
+		location, errLoad := time.LoadLocation(tzid.value)
+		if errLoad != nil {
+			if location, err = parseICalTimeZone(tzid.value, tz); err != nil {
+				return
+			}
+		}
+		locations[tzid.value] = location
+	}
+	return
+}
+
+func parseICalOffset(prop *icalProperty) (offset int, err error) {
+	value := prop.value
+	if len(value) != 5 && len(value) != 7 || (value[0] != '+' && value[0] != '-') {
+		return 0, icalError(prop.line, "invalid offset %q", value)
+	}
+
+	for i, unit := range []int{3600, 60, 1} {
+		if 1+2*i >= len(value) {
+			break
+		}
+
+		n, errAtoi := strconv.Atoi(value[1+2*i : 3+2*i])
+		if errAtoi != nil {
+			return 0, icalError(prop.line, "invalid offset %q", value)
+		}
+		offset += n * unit
+	}
+
+	if value[0] == '-' {
+		offset = -offset
+	}
+	return
+}
+
+func (s *Scheduler) parseICalTimes(prop *icalProperty, locations map[string]*time.Location) (res []time.Time, err error) {
+	if value := prop.params["VALUE"]; value == "PERIOD" {
+		return nil, icalError(prop.line, "%s of periods is not supported", prop.name)
+	}
+
+	location := s.locationTZ
+	if tzid, isExists := prop.params["TZID"]; isExists {
+		if location, isExists = locations[tzid]; !isExists {
+			if location, err = time.LoadLocation(tzid); err != nil {
+				return nil, icalError(prop.line, "unknown TZID %q", tzid)
+			}
+		}
+	}
+
+	for _, value := range strings.Split(prop.value, ",") {
+		var dateTime time.Time
+		switch {
+		case len(value) == len(rruleDateFormat):
+			dateTime, err = time.ParseInLocation(rruleDateFormat, value, location)
+		case strings.HasSuffix(value, "Z"):
+			dateTime, err = time.Parse(icalDateTimeFormat+"Z", value)
+		default:
+			dateTime, err = time.ParseInLocation(icalDateTimeFormat, value, location)
+		}
+
+		if err != nil {
+			return nil, icalError(prop.line, "invalid date time %q of %s", value, prop.name)
+		}
+		res = append(res, dateTime)
+	}
+	return
+}
+
+func (s *Scheduler) toICalJob(event *icalComponent, locations map[string]*time.Location, opt *ICalImportOption) (job *icalJob, err error) {
+	uid, dtStart := event.property("UID"), event.property("DTSTART")
+	if uid == nil || uid.value == "" || dtStart == nil {
+		return nil, icalError(event.line, "UID and DTSTART are required")
+	}
+
+	starts, err := s.parseICalTimes(dtStart, locations)
+	if err != nil {
+		return
+	}
+
+	rule, line := "", event.line
+	if prop := event.property("RRULE"); prop != nil {
+		rule, line = prop.value, prop.line
+	}
+
+	job = &icalJob{
+		key:     unescapeICalText(uid.value),
+		handler: opt.Handler,
+		tags:    []string{icalSourceTag + opt.Source},
+	}
+
+	if job.trigger, err = ParseRRule(starts[0], rule); err != nil {
+		return nil, icalError(line, "%v", err)
+	}
+
+	for _, name := range []string{"EXDATE", "RDATE"} {
+		for _, prop := range event.all(name) {
+			var dateTimes []time.Time
+			if dateTimes, err = s.parseICalTimes(prop, locations); err != nil {
+				return
+			}
+
+			if name == "EXDATE" {
+				job.trigger.Exclude(dateTimes...)
+			} else {
+				job.trigger.Include(dateTimes...)
+			}
+		}
+	}
+
+	if prop := event.property(icalHandlerProperty); prop != nil {
+		job.handler = unescapeICalText(prop.value)
+	}
+
+	for _, prop := range event.all("CATEGORIES") {
+		job.tags = append(job.tags, splitICalText(prop.value)...)
+	}
+	return
+}
+
+// ImportICal adds a job for every VEVENT of the calendar read from r, keyed
+// by its UID and bound to the handler of its X-SCHEDULER-HANDLER property or
+// opt.Handler. Every event is added with a RRule trigger built from its
+// DTSTART, RRULE, EXDATE and RDATE, CATEGORIES are added as tags. Importing
+// the same source again replaces the changed events, cancels the removed
+// ones and leaves the others untouched. Events without a next date time,
+// bound to an unknown handler, overriding an occurrence with RECURRENCE-ID
+// or whose key belongs to another job are skipped.
+func (s *Scheduler) ImportICal(r io.Reader, opts ...ICalImportOption) (res *ICalImportResult, err error) {
+	opt := ICalImportOption{}
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.Source == "" {
+		opt.Source = defaultICalSource
+	}
+
+	root, err := parseICal(r)
+	if err != nil {
+		return
+	}
+
+	locations, err := icalLocations(root)
+	if err != nil {
+		return
+	}
+
+	var jobs []*icalJob
+	res = &ICalImportResult{}
+	for _, event := range root.children("VEVENT") {
+		if event.property("RECURRENCE-ID") != nil {
+			if uid := event.property("UID"); uid != nil {
+				res.Skipped = append(res.Skipped, unescapeICalText(uid.value))
+			}
+			continue
+		}
+
+		var job *icalJob
+		if job, err = s.toICalJob(event, locations, &opt); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	seen := make(map[string]struct{})
+	for _, job := range jobs {
+		seen[job.key] = struct{}{}
+		s.importICalJob(job, res)
+	}
+
+	sourced, err := s.Query(QueryOption{Tags: []string{icalSourceTag + opt.Source}, Sort: QuerySortKey})
+	if err != nil {
+		return
+	}
+
+	for _, info := range sourced.Jobs {
+		if _, isExists := seen[info.Key]; isExists {
+			continue
+		}
+
+		if errCancel := s.Cancel(info.Key); errCancel == nil {
+			res.Cancelled = append(res.Cancelled, info.Key)
+		}
+	}
+	return
+}
+
+func (s *Scheduler) importICalJob(job *icalJob, res *ICalImportResult) {
+	fn, err := s.handler(job.handler)
+	if err != nil {
+		res.Skipped = append(res.Skipped, job.key)
+		return
+	}
+
+	info, err := s.Get(job.key)
+	if err != nil {
+		if err = s.AddTriggerHandler(job.key, job.trigger, job.handler, JobOption{Tags: job.tags}); err != nil {
+			res.Skipped = append(res.Skipped, job.key)
+			return
+		}
+
+		res.Added = append(res.Added, job.key)
+		return
+	}
+
+	if !containsString(info.Tags, job.tags[0]) {
+		res.Skipped = append(res.Skipped, job.key)
+		return
+	}
+
+	if info.Handler == job.handler && info.Trigger == job.trigger.String() && equalTags(info.Tags, job.tags) {
+		res.Unchanged = append(res.Unchanged, job.key)
+		return
+	}
+
+	param, err := s.triggerParam(job.trigger, []JobOption{{Tags: job.tags}})
+	if err != nil {
+		if s.Cancel(job.key) == nil {
+			res.Cancelled = append(res.Cancelled, job.key)
+		}
+		return
+	}
+
+	param.handler = job.handler
+	if err = s.replace(job.key, param, fn); err != nil {
+		res.Skipped = append(res.Skipped, job.key)
+		return
+	}
+
+	res.Replaced = append(res.Replaced, job.key)
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,293 @@
+package scheduler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func icalCalendar(lines ...string) *strings.Reader {
+	return strings.NewReader(strings.Join(append(append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...), "END:VCALENDAR"), "\r\n"))
+}
+
+var (
+	icalTimeZone = []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Custom Standard Time",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:+0700",
+		"TZOFFSETTO:+0700",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	}
+
+	icalFilter = []string{
+		"BEGIN:VEVENT",
+		"UID:hvac-filter",
+		"DTSTART;TZID=Custom Standard Time:20990105T080000",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		"EXDATE;TZID=Custom Standard Time:20990112T080000",
+		"CATEGORIES:hvac,weekly",
+		"X-SCHEDULER-HANDLER:maintenance",
+		"END:VEVENT",
+	}
+)
+
+func newICalScheduler(t *testing.T) *Scheduler {
+	schedule := NewScheduler()
+	assert.Nil(t, schedule.RegisterHandler("maintenance", fn))
+	assert.Nil(t, schedule.RegisterHandler("drill", fn))
+	return schedule
+}
+
+func TestImportICal(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Import events", func(t *testing.T) {
+			t.Parallel()
+			schedule := newICalScheduler(t)
+			calendar := append(append([]string{}, icalTimeZone...), icalFilter...)
+			calendar = append(calendar,
+				"BEGIN:VEVENT",
+				"UID:fire-drill",
+				"DTSTART:20990301T100000Z",
+				"RDATE:20990901T100000Z",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:past",
+				"DTSTART:20000101T100000Z",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:hvac-filter",
+				"RECURRENCE-ID;TZID=Custom Standard Time:20990119T080000",
+				"DTSTART;TZID=Custom Standard Time:20990120T080000",
+				"END:VEVENT",
+			)
+
+			res, err := schedule.ImportICal(icalCalendar(calendar...), ICalImportOption{Handler: "drill", Source: "facilities"})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"hvac-filter", "fire-drill"}, res.Added)
+			assert.ElementsMatch(t, []string{"hvac-filter", "past"}, res.Skipped)
+
+			info, err := schedule.Get("hvac-filter")
+			assert.Nil(t, err)
+			assert.Equal(t, "maintenance", info.Handler)
+			assert.Equal(t, "Custom Standard Time", info.TimeZone)
+			assert.True(t, info.DateTime.Equal(time.Date(2099, time.January, 5, 1, 0, 0, 0, time.UTC)))
+			assert.Equal(t, []string{"ical:facilities", "hvac", "weekly"}, info.Tags)
+
+			next, ok := info.Recurrence.Next(info.DateTime)
+			assert.True(t, ok)
+			assert.True(t, next.Equal(time.Date(2099, time.January, 19, 1, 0, 0, 0, time.UTC)))
+
+			info, err = schedule.Get("fire-drill")
+			assert.Nil(t, err)
+			assert.Equal(t, "drill", info.Handler)
+			next, ok = info.Recurrence.Next(info.DateTime)
+			assert.True(t, ok)
+			assert.True(t, next.Equal(time.Date(2099, time.September, 1, 10, 0, 0, 0, time.UTC)))
+		})
+
+		t.Run("Import again", func(t *testing.T) {
+			t.Parallel()
+			schedule := newICalScheduler(t)
+			drill := func(dtStart string) []string {
+				return []string{"BEGIN:VEVENT", "UID:fire-drill", "DTSTART:" + dtStart, "X-SCHEDULER-HANDLER:drill", "END:VEVENT"}
+			}
+
+			calendar := append(append(append([]string{}, icalTimeZone...), icalFilter...), drill("20990301T100000Z")...)
+			res, err := schedule.ImportICal(icalCalendar(calendar...))
+			assert.Nil(t, err)
+			assert.Len(t, res.Added, 2)
+
+			calendar = append(append(append([]string{}, icalTimeZone...), icalFilter...), drill("20990302T100000Z")...)
+			res, err = schedule.ImportICal(icalCalendar(calendar...))
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"hvac-filter"}, res.Unchanged)
+			assert.Equal(t, []string{"fire-drill"}, res.Replaced)
+			info, err := schedule.Get("fire-drill")
+			assert.Nil(t, err)
+			assert.True(t, info.DateTime.Equal(time.Date(2099, time.March, 2, 10, 0, 0, 0, time.UTC)))
+
+			res, err = schedule.ImportICal(icalCalendar(drill("20990302T100000Z")...))
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"fire-drill"}, res.Unchanged)
+			assert.Equal(t, []string{"hvac-filter"}, res.Cancelled)
+			_, err = schedule.Get("hvac-filter")
+			assert.Equal(t, ErrKeyIsNotExists, err)
+		})
+
+		t.Run("Time zone with daylight saving time", func(t *testing.T) {
+			t.Parallel()
+			// Outlook names the time zones with their Windows names.
+			pacific := []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Pacific Standard Time",
+				"BEGIN:STANDARD",
+				"DTSTART:16010101T020000",
+				"TZOFFSETFROM:-0700",
+				"TZOFFSETTO:-0800",
+				"RRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11",
+				"END:STANDARD",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:16010101T020000",
+				"TZOFFSETFROM:-0800",
+				"TZOFFSETTO:-0700",
+				"RRULE:FREQ=YEARLY;BYDAY=2SU;BYMONTH=3",
+				"END:DAYLIGHT",
+				"END:VTIMEZONE",
+			}
+
+			root, err := parseICal(icalCalendar(append(append([]string{}, pacific...),
+				"BEGIN:VTIMEZONE",
+				"TZID:Custom Eastern",
+				"BEGIN:STANDARD",
+				"DTSTART:19671029T020000",
+				"TZOFFSETFROM:-0400",
+				"TZOFFSETTO:-0500",
+				"TZNAME:EST",
+				"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=SU;BYMONTHDAY=-7,-6,-5,-4,-3,-2,-1;UNTIL=20061029T060000Z",
+				"END:STANDARD",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:19870405T020000",
+				"TZOFFSETFROM:-0500",
+				"TZOFFSETTO:-0400",
+				"TZNAME:EDT",
+				"RRULE:FREQ=YEARLY;BYMONTH=4;BYDAY=1SU;UNTIL=20060402T070000Z",
+				"END:DAYLIGHT",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:20070311T020000",
+				"TZOFFSETFROM:-0500",
+				"TZOFFSETTO:-0400",
+				"TZNAME:EDT",
+				"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=SU;BYMONTHDAY=8,9,10,11,12,13,14",
+				"END:DAYLIGHT",
+				"BEGIN:STANDARD",
+				"DTSTART:20071104T020000",
+				"TZOFFSETFROM:-0400",
+				"TZOFFSETTO:-0500",
+				"TZNAME:EST",
+				"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			)...))
+			assert.Nil(t, err)
+			locations, err := icalLocations(root)
+			assert.Nil(t, err)
+
+			testCases := []struct {
+				tzid     string
+				dateTime time.Time
+				name     string
+				offset   int
+			}{
+				{"Pacific Standard Time", time.Date(2099, time.March, 8, 9, 59, 59, 0, time.UTC), "STANDARD", -8 * 3600},
+				{"Pacific Standard Time", time.Date(2099, time.March, 8, 10, 0, 0, 0, time.UTC), "DAYLIGHT", -7 * 3600},
+				{"Pacific Standard Time", time.Date(2099, time.November, 1, 8, 59, 59, 0, time.UTC), "DAYLIGHT", -7 * 3600},
+				{"Pacific Standard Time", time.Date(2099, time.November, 1, 9, 0, 0, 0, time.UTC), "STANDARD", -8 * 3600},
+				{"Custom Eastern", time.Date(2000, time.April, 2, 6, 59, 59, 0, time.UTC), "EST", -5 * 3600},
+				{"Custom Eastern", time.Date(2000, time.April, 2, 7, 0, 0, 0, time.UTC), "EDT", -4 * 3600},
+				{"Custom Eastern", time.Date(2000, time.October, 29, 6, 0, 0, 0, time.UTC), "EST", -5 * 3600},
+				{"Custom Eastern", time.Date(2010, time.March, 14, 7, 0, 0, 0, time.UTC), "EDT", -4 * 3600},
+				{"Custom Eastern", time.Date(2010, time.November, 7, 6, 0, 0, 0, time.UTC), "EST", -5 * 3600},
+				{"Custom Eastern", time.Date(2099, time.March, 8, 6, 59, 59, 0, time.UTC), "EST", -5 * 3600},
+				{"Custom Eastern", time.Date(2099, time.March, 8, 7, 0, 0, 0, time.UTC), "EDT", -4 * 3600},
+			}
+
+			for _, testCase := range testCases {
+				name, offset := testCase.dateTime.In(locations[testCase.tzid]).Zone()
+				assert.Equal(t, testCase.name, name, testCase.tzid+" "+testCase.dateTime.String())
+				assert.Equal(t, testCase.offset, offset, testCase.tzid+" "+testCase.dateTime.String())
+			}
+
+			schedule := newICalScheduler(t)
+			calendar := append(append([]string{}, pacific...),
+				"BEGIN:VEVENT",
+				"UID:standup",
+				"DTSTART;TZID=Pacific Standard Time:20990302T090000",
+				"RRULE:FREQ=WEEKLY",
+				"X-SCHEDULER-HANDLER:maintenance",
+				"END:VEVENT",
+			)
+			_, err = schedule.ImportICal(icalCalendar(calendar...))
+			assert.Nil(t, err)
+
+			info, err := schedule.Get("standup")
+			assert.Nil(t, err)
+			assert.True(t, info.DateTime.Equal(time.Date(2099, time.March, 2, 17, 0, 0, 0, time.UTC)))
+			next, ok := info.Recurrence.Next(info.DateTime)
+			assert.True(t, ok)
+			assert.True(t, next.Equal(time.Date(2099, time.March, 9, 16, 0, 0, 0, time.UTC)))
+		})
+
+		t.Run("Round trip with the calendar converter", func(t *testing.T) {
+			t.Parallel()
+			schedule := newICalScheduler(t)
+			calendar := append(append([]string{}, icalTimeZone...), icalFilter...)
+			_, err := schedule.ImportICal(icalCalendar(calendar...))
+			assert.Nil(t, err)
+
+			buf := &bytes.Buffer{}
+			_, err = schedule.List(buf, NewICalResponse())
+			assert.Nil(t, err)
+			assert.Contains(t, buf.String(), "RRULE:FREQ=WEEKLY;BYDAY=MO\r\n")
+
+			imported := newICalScheduler(t)
+			res, err := imported.ImportICal(buf)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"hvac-filter"}, res.Added)
+
+			expected, err := schedule.Get("hvac-filter")
+			assert.Nil(t, err)
+			actual, err := imported.Get("hvac-filter")
+			assert.Nil(t, err)
+			assert.Equal(t, expected.Trigger, actual.Trigger)
+			assert.Equal(t, expected.Handler, actual.Handler)
+			assert.True(t, expected.DateTime.Equal(actual.DateTime))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Skip keys of other jobs and unknown handlers", func(t *testing.T) {
+			t.Parallel()
+			schedule := newICalScheduler(t)
+			assert.Nil(t, schedule.Add("hvac-filter", time.Hour, fn))
+
+			res, err := schedule.ImportICal(icalCalendar(append(append([]string{}, icalTimeZone...), icalFilter...)...))
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"hvac-filter"}, res.Skipped)
+
+			res, err = schedule.ImportICal(icalCalendar("BEGIN:VEVENT", "UID:other", "DTSTART:20990301T100000Z", "END:VEVENT"))
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"other"}, res.Skipped)
+		})
+
+		t.Run("Invalid calendar", func(t *testing.T) {
+			t.Parallel()
+			schedule := newICalScheduler(t)
+			testCases := map[string]*strings.Reader{
+				"line 3: UID and DTSTART are required":     icalCalendar("BEGIN:VEVENT", "DTSTART:20990301T100000Z", "END:VEVENT"),
+				"line 4: unexpected END:VTODO":             icalCalendar("BEGIN:VEVENT", "END:VTODO"),
+				"line 5: unknown TZID":                     icalCalendar("BEGIN:VEVENT", "UID:a", "DTSTART;TZID=Nowhere:20990301T100000", "END:VEVENT"),
+				"line 6: the rrule format is invalid":      icalCalendar("BEGIN:VEVENT", "UID:a", "DTSTART:20990301T100000Z", "RRULE:FREQ=DAILY;BYSETPOS=1", "END:VEVENT"),
+				"line 3: STANDARD or DAYLIGHT is required": icalCalendar("BEGIN:VTIMEZONE", "TZID:Nowhere", "END:VTIMEZONE"),
+				`line 3: DAYLIGHT RRULE "FREQ=MONTHLY;BYDAY=2SU": only a yearly rule`: icalCalendar(
+					"BEGIN:VTIMEZONE", "TZID:Nowhere",
+					"BEGIN:STANDARD", "DTSTART:16010101T020000", "TZOFFSETFROM:+0100", "TZOFFSETTO:+0000", "RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU", "END:STANDARD",
+					"BEGIN:DAYLIGHT", "DTSTART:16010101T020000", "TZOFFSETFROM:+0000", "TZOFFSETTO:+0100", "RRULE:FREQ=MONTHLY;BYDAY=2SU", "END:DAYLIGHT",
+					"END:VTIMEZONE",
+				),
+				"VCALENDAR is required": strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT"),
+			}
+
+			for expected, r := range testCases {
+				_, err := schedule.ImportICal(r)
+				assert.ErrorIs(t, err, ErrICalFormat)
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	})
+}
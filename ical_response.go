@@ -0,0 +1,354 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultICalProdID = "-//go-simple-scheduler//EN"
+
+	icalDateTimeFormat = "20060102T150405"
+	icalLineLength     = 75
+
+	// icalHandlerProperty names the handler of an event, see ImportICal.
+	icalHandlerProperty = "X-SCHEDULER-HANDLER"
+
+	// icalTriggerProperty describes a trigger having no equivalent RRULE.
+	icalTriggerProperty = "X-SCHEDULER-TRIGGER"
+)
+
+type ICalConfig struct {
+	ProdID string
+
+	// Stamp is the DTSTAMP of every event, default to the time of rendering.
+	Stamp time.Time
+}
+
+// NewICalResponse renders the jobs as an RFC 5545 calendar with one VEVENT
+// per job. The UID is the key and DTSTART keeps the time zone of the job, a
+// VTIMEZONE is written for every time zone with its transitions during the
+// year of the first job using it. Jobs added with a RRule trigger are
+// written with their DTSTART, RRULE, EXDATE and RDATE. Jobs added with an
+// Interval or a Cron trigger are written with an equivalent RRULE starting
+// at their next date time, a Cron restricting both the day of month and
+// the day of week has none. A trigger without RRULE is described by a
+// X-SCHEDULER-TRIGGER property.
+func NewICalResponse(configs ...ICalConfig) ListConverter {
+	config := ICalConfig{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.ProdID == "" {
+		config.ProdID = defaultICalProdID
+	}
+
+	return &icalResponse{config: config}
+}
+
+type icalResponse struct {
+	config ICalConfig
+}
+
+func (d *icalResponse) Convert(data []*ResponseScheduler) ([]byte, error) {
+	return convertStream(d, data)
+}
+
+func (d *icalResponse) Stream(w io.Writer, next ResponseIterator) (n int, err error) {
+	cw := &countWriter{w: w}
+	stamp := d.config.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	writeICalLine(cw, "BEGIN:VCALENDAR")
+	writeICalLine(cw, "VERSION:2.0")
+	writeICalLine(cw, "PRODID:"+escapeICalText(d.config.ProdID))
+
+	zones := make(map[string]struct{})
+	for cw.err == nil {
+		res, ok := next()
+		if !ok {
+			break
+		}
+
+		start := res.Time
+		rrule, isRRule := res.Recurrence.(*RRule)
+		cron, isCron := res.Recurrence.(*Cron)
+		switch {
+		case isRRule:
+			start = rrule.Start()
+		case isCron:
+			start = start.In(cron.Location())
+		}
+
+		if zone := icalZone(start); zone != "" {
+			if _, isExists := zones[zone]; !isExists {
+				zones[zone] = struct{}{}
+				writeICalTimeZone(cw, start.Location(), start.Year())
+			}
+		}
+
+		writeICalLine(cw, "BEGIN:VEVENT")
+		writeICalLine(cw, "UID:"+escapeICalText(res.Key))
+		writeICalLine(cw, "DTSTAMP:"+stamp.UTC().Format(icalDateTimeFormat)+"Z")
+		writeICalLine(cw, "DTSTART"+icalDateTime(start))
+		switch rule := icalRule(res.Recurrence, start); {
+		case isRRule:
+			writeICalRecurrence(cw, rrule)
+		case rule != "":
+			writeICalLine(cw, "RRULE:"+rule)
+		case res.Recurrence != nil:
+			writeICalLine(cw, icalTriggerProperty+":"+escapeICalText(res.Recurrence.String()))
+		}
+
+		writeICalLine(cw, "SUMMARY:"+escapeICalText(res.Key))
+		if description := icalDescription(res); description != "" {
+			writeICalLine(cw, "DESCRIPTION:"+escapeICalText(description))
+		}
+
+		if len(res.Tags) > 0 {
+			tags := make([]string, len(res.Tags))
+			for i, tag := range res.Tags {
+				tags[i] = escapeICalText(tag)
+			}
+			writeICalLine(cw, "CATEGORIES:"+strings.Join(tags, ","))
+		}
+
+		if res.Handler != "" {
+			writeICalLine(cw, icalHandlerProperty+":"+escapeICalText(res.Handler))
+		}
+		writeICalLine(cw, "END:VEVENT")
+	}
+
+	writeICalLine(cw, "END:VCALENDAR")
+	return cw.n, cw.err
+}
+
+func writeICalRecurrence(cw *countWriter, rrule *RRule) {
+	if rule := rrule.Rule(); rule != "" {
+		writeICalLine(cw, "RRULE:"+rule)
+	}
+
+	for _, exDate := range rrule.ExDates() {
+		writeICalLine(cw, "EXDATE"+icalDateTime(exDate))
+	}
+
+	for _, rDate := range rrule.RDates() {
+		writeICalLine(cw, "RDATE"+icalDateTime(rDate))
+	}
+}
+
+// icalRule returns the RRULE equivalent to an Interval or a Cron trigger
+// from start, empty when there is none.
+func icalRule(trigger Trigger, start time.Time) string {
+	switch trigger := trigger.(type) {
+	case *Interval:
+		return icalIntervalRule(trigger, start)
+	case *Cron:
+		return icalCronRule(trigger)
+	default:
+		return ""
+	}
+}
+
+func icalIntervalRule(interval *Interval, start time.Time) string {
+	for _, unit := range []struct {
+		freq     Frequency
+		duration time.Duration
+	}{
+		{FrequencyDaily, 24 * time.Hour},
+		{FrequencyHourly, time.Hour},
+		{FrequencyMinutely, time.Minute},
+		{FrequencySecondly, time.Second},
+	} {
+		// A day of a time zone other than UTC may last 23 or 25 hours.
+		if unit.freq == FrequencyDaily && icalZone(start) != "" {
+			continue
+		}
+
+		if interval.interval >= unit.duration && interval.interval%unit.duration == 0 {
+			return "FREQ=" + frequencyNames[unit.freq] + ";INTERVAL=" + strconv.FormatInt(int64(interval.interval/unit.duration), 10)
+		}
+	}
+	return ""
+}
+
+// icalCronRule returns a daily RRULE limited to the months and days of c
+// and expanded to its hours and minutes. A RRULE limits by both BYMONTHDAY
+// and BYDAY, so c has none when either of its days may match.
+func icalCronRule(c *Cron) string {
+	if !c.dayOfMonthStar && !c.dayOfWeekStar {
+		return ""
+	}
+
+	parts := []string{"FREQ=" + frequencyNames[FrequencyDaily]}
+	for _, by := range []struct {
+		name   string
+		field  int
+		always bool
+	}{
+		{"BYMONTH", cronMonth, false},
+		{"BYMONTHDAY", cronDayOfMonth, false},
+		{"BYDAY", cronDayOfWeek, false},
+		{"BYHOUR", cronHour, true},
+		{"BYMINUTE", cronMinute, true},
+	} {
+		var values []string
+		isAll := true
+		field := cronFields[by.field]
+		if by.field == cronDayOfWeek {
+			field.max = 6
+		}
+
+		for v := field.min; v <= field.max; v++ {
+			switch {
+			case !c.has(by.field, v):
+				isAll = false
+			case by.field == cronDayOfWeek:
+				values = append(values, weekdayNames[v])
+			default:
+				values = append(values, strconv.Itoa(v))
+			}
+		}
+
+		if by.always || !isAll {
+			parts = append(parts, by.name+"="+strings.Join(values, ","))
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// icalZone returns the TZID of dateTime, empty when it is written in UTC.
+func icalZone(dateTime time.Time) string {
+	switch name := dateTime.Location().String(); name {
+	case "UTC", "Local", "":
+		return ""
+	default:
+		return name
+	}
+}
+
+func icalDateTime(dateTime time.Time) string {
+	zone := icalZone(dateTime)
+	if zone == "" {
+		return ":" + dateTime.UTC().Format(icalDateTimeFormat) + "Z"
+	}
+
+	return ";TZID=" + zone + ":" + dateTime.Format(icalDateTimeFormat)
+}
+
+func icalDescription(res *ResponseScheduler) string {
+	var lines []string
+	if res.Handler != "" {
+		lines = append(lines, "Handler: "+res.Handler)
+	}
+
+	if res.Status != "" {
+		lines = append(lines, "Status: "+string(res.Status))
+	}
+
+	if len(res.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(res.Tags, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeICalTimeZone(cw *countWriter, location *time.Location, year int) {
+	writeICalLine(cw, "BEGIN:VTIMEZONE")
+	writeICalLine(cw, "TZID:"+location.String())
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+	end := start.AddDate(1, 0, 0)
+	_, offset := start.Zone()
+	transitions := 0
+	for t := start; t.Before(end); t = t.Add(time.Hour) {
+		name, next := t.Zone()
+		if next == offset {
+			continue
+		}
+
+		transition := searchICalTransition(t.Add(-time.Hour), t)
+		writeICalObservance(cw, transition, name, offset, next)
+		offset = next
+		transitions++
+	}
+
+	if transitions == 0 {
+		name, _ := start.Zone()
+		writeICalObservance(cw, start, name, offset, offset)
+	}
+	writeICalLine(cw, "END:VTIMEZONE")
+}
+
+// searchICalTransition returns the first minute in (before, after] having
+// the offset of after.
+func searchICalTransition(before, after time.Time) time.Time {
+	_, offset := after.Zone()
+	for after.Sub(before) > time.Minute {
+		middle := before.Add(after.Sub(before) / 2).Truncate(time.Minute)
+		if _, o := middle.Zone(); o == offset {
+			after = middle
+		} else {
+			before = middle
+		}
+	}
+	return after
+}
+
+// writeICalObservance writes the observance starting at transition, its
+// DTSTART is the local time before the transition.
+func writeICalObservance(cw *countWriter, transition time.Time, name string, from, to int) {
+	component := "STANDARD"
+	if transition.IsDST() {
+		component = "DAYLIGHT"
+	}
+
+	writeICalLine(cw, "BEGIN:"+component)
+	writeICalLine(cw, "DTSTART:"+transition.In(time.FixedZone("", from)).Format(icalDateTimeFormat))
+	writeICalLine(cw, "TZOFFSETFROM:"+icalOffset(from))
+	writeICalLine(cw, "TZOFFSETTO:"+icalOffset(to))
+	writeICalLine(cw, "TZNAME:"+escapeICalText(name))
+	writeICalLine(cw, "END:"+component)
+}
+
+func icalOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+func escapeICalText(text string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+	).Replace(text)
+}
+
+// writeICalLine folds line into lines of at most 75 octets without breaking
+// UTF-8 sequences, as required by RFC 5545.
+func writeICalLine(cw *countWriter, line string) {
+	length := icalLineLength
+	for len(line) > length {
+		cut := length
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		cw.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		length = icalLineLength - 1
+	}
+
+	cw.WriteString(line + "\r\n")
+}
@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestICalResponse(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Calendar of jobs", func(t *testing.T) {
+			location, err := time.LoadLocation("America/New_York")
+			assert.Nil(t, err)
+			stamp := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+			data := []*ResponseScheduler{
+				{Key: "maintenance;1", Time: time.Date(2022, 11, 20, 9, 0, 0, 0, location), Handler: "handler", Tags: []string{"db", "weekly"}},
+				{Key: "add#2", Time: time.Date(2022, 11, 20, 9, 0, 0, 0, time.UTC)},
+			}
+
+			res, err := NewICalResponse(ICalConfig{Stamp: stamp}).Convert(data)
+			assert.Nil(t, err)
+			assert.Equal(t, strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//go-simple-scheduler//EN",
+				"BEGIN:VTIMEZONE",
+				"TZID:America/New_York",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:20220313T020000",
+				"TZOFFSETFROM:-0500",
+				"TZOFFSETTO:-0400",
+				"TZNAME:EDT",
+				"END:DAYLIGHT",
+				"BEGIN:STANDARD",
+				"DTSTART:20221106T020000",
+				"TZOFFSETFROM:-0400",
+				"TZOFFSETTO:-0500",
+				"TZNAME:EST",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+				"BEGIN:VEVENT",
+				"UID:maintenance\\;1",
+				"DTSTAMP:20220801T000000Z",
+				"DTSTART;TZID=America/New_York:20221120T090000",
+				"SUMMARY:maintenance\\;1",
+				"DESCRIPTION:Handler: handler\\nTags: db\\, weekly",
+				"CATEGORIES:db,weekly",
+				"X-SCHEDULER-HANDLER:handler",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:add#2",
+				"DTSTAMP:20220801T000000Z",
+				"DTSTART:20221120T090000Z",
+				"SUMMARY:add#2",
+				"END:VEVENT",
+				"END:VCALENDAR",
+				"",
+			}, "\r\n"), string(res))
+		})
+
+		t.Run("Recurring triggers", func(t *testing.T) {
+			jakarta, err := time.LoadLocation("Asia/Jakarta")
+			assert.Nil(t, err)
+			start := time.Date(2030, 1, 7, 9, 30, 0, 0, time.UTC)
+			weekdays, err := ParseCron("30 9 * 1-6 mon-fri", jakarta)
+			assert.Nil(t, err)
+			either, err := ParseCron("0 0 1 * mon", jakarta)
+			assert.Nil(t, err)
+
+			for _, tc := range []struct {
+				trigger Trigger
+				start   time.Time
+				rule    string
+			}{
+				{NewInterval(start, 90*time.Minute), start, "RRULE:FREQ=MINUTELY;INTERVAL=90"},
+				{NewInterval(start, 48*time.Hour), start, "RRULE:FREQ=DAILY;INTERVAL=2"},
+				{NewInterval(start.In(jakarta), 24*time.Hour), start.In(jakarta), "RRULE:FREQ=HOURLY;INTERVAL=24"},
+				{weekdays, start.In(jakarta), "RRULE:FREQ=DAILY;BYMONTH=1,2,3,4,5,6;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9;BYMINUTE=30"},
+				{either, start, "X-SCHEDULER-TRIGGER:0 0 1 * mon"},
+				{NewInterval(start, 1500*time.Millisecond), start, "X-SCHEDULER-TRIGGER:every 1.5s"},
+				{&intervalTrigger{start: start, interval: time.Hour, total: 3}, start, "X-SCHEDULER-TRIGGER:every 1h0m0s"},
+			} {
+				next, ok := tc.trigger.Next(tc.start.Add(-time.Second))
+				assert.True(t, ok)
+				res, err := NewICalResponse().Convert([]*ResponseScheduler{{Key: "add#1", Time: next, Recurrence: tc.trigger}})
+				assert.Nil(t, err)
+				assert.Contains(t, strings.ReplaceAll(string(res), "\r\n ", ""), "\r\n"+tc.rule+"\r\n")
+
+				rule, isRule := strings.CutPrefix(tc.rule, "RRULE:")
+				if !isRule {
+					assert.NotContains(t, string(res), "RRULE")
+					continue
+				}
+
+				rrule, err := ParseRRule(next, rule)
+				assert.Nil(t, err, rule)
+				assert.Equal(t, nextDateTimes(tc.trigger, next.Add(-time.Second), 20), nextDateTimes(rrule, next.Add(-time.Second), 20), rule)
+			}
+		})
+
+		t.Run("Fold long lines", func(t *testing.T) {
+			key := strings.Repeat("ä", 100)
+			res, err := NewICalResponse().Convert([]*ResponseScheduler{{Key: key, Time: time.Now()}})
+			assert.Nil(t, err)
+
+			lines := strings.Split(string(res), "\r\n")
+			for _, line := range lines {
+				assert.LessOrEqual(t, len(line), 75)
+			}
+			assert.Contains(t, strings.ReplaceAll(string(res), "\r\n ", ""), "UID:"+key+"\r\n")
+		})
+
+		t.Run("List as calendar", func(t *testing.T) {
+			schedule := NewScheduler(Config{TimeZone: "Asia/Jakarta"})
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+
+			buf := &bytes.Buffer{}
+			_, err := schedule.List(buf, NewListConverter(ListTypeICal))
+			assert.Nil(t, err)
+			assert.Contains(t, buf.String(), "TZID:Asia/Jakarta\r\n")
+			assert.Contains(t, buf.String(), "TZOFFSETTO:+0700\r\n")
+			assert.Contains(t, buf.String(), "UID:add#1\r\n")
+		})
+	})
+}
@@ -0,0 +1,264 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// icalObservance is a STANDARD or DAYLIGHT component of a VTIMEZONE.
+type icalObservance struct {
+	name   string
+	isDST  bool
+	offset int
+	rule   *RRule
+
+	// start is the time of day of the onsets, in seconds.
+	start int
+}
+
+type icalTransition struct {
+	when       int64
+	observance *icalObservance
+}
+
+// tzifType is a local time type of a TZif file.
+type tzifType struct {
+	name   string
+	isDST  bool
+	offset int
+}
+
+// parseICalTimeZone returns the time zone of a VTIMEZONE from its observances. The
+// onsets of the DTSTART, RDATE and bounded RRULE of every observance are
+// transitions, the RRULE without COUNT nor UNTIL of the latest STANDARD and
+// DAYLIGHT become the rule of the time zone after them, like
+// "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU".
+func parseICalTimeZone(tzid string, tz *icalComponent) (location *time.Location, err error) {
+	var (
+		transitions []icalTransition
+		ongoing     = map[bool]*icalObservance{}
+	)
+
+	for _, component := range tz.components {
+		if component.name != "STANDARD" && component.name != "DAYLIGHT" {
+			continue
+		}
+
+		start, from, to := component.property("DTSTART"), component.property("TZOFFSETFROM"), component.property("TZOFFSETTO")
+		if start == nil || from == nil || to == nil {
+			return nil, icalError(component.line, "DTSTART, TZOFFSETFROM and TZOFFSETTO are required")
+		}
+
+		observance := &icalObservance{name: component.name, isDST: component.name == "DAYLIGHT"}
+		if prop := component.property("TZNAME"); prop != nil {
+			observance.name = unescapeICalText(prop.value)
+		}
+
+		var offsetFrom int
+		if offsetFrom, err = parseICalOffset(from); err != nil {
+			return
+		}
+
+		if observance.offset, err = parseICalOffset(to); err != nil {
+			return
+		}
+
+		// The onsets are local times of the offset in use before them.
+		zoneFrom := time.FixedZone("", offsetFrom)
+		onset, errParse := time.ParseInLocation(icalDateTimeFormat, start.value, zoneFrom)
+		if errParse != nil {
+			return nil, icalError(start.line, "invalid date time %q of DTSTART", start.value)
+		}
+		observance.start = onset.Hour()*3600 + onset.Minute()*60 + onset.Second()
+		onsets := []time.Time{onset}
+
+		for _, prop := range component.all("RDATE") {
+			for _, value := range strings.Split(prop.value, ",") {
+				dateTime, errParse := time.ParseInLocation(icalDateTimeFormat, value, zoneFrom)
+				if errParse != nil {
+					return nil, icalError(prop.line, "invalid date time %q of RDATE", value)
+				}
+				onsets = append(onsets, dateTime)
+			}
+		}
+
+		if prop := component.property("RRULE"); prop != nil {
+			if observance.rule, err = ParseRRule(onset, prop.value); err != nil {
+				return nil, icalError(prop.line, "%v", err)
+			}
+
+			if observance.rule.count == 0 && observance.rule.until.IsZero() {
+				if latest := ongoing[observance.isDST]; latest == nil || latest.rule.start.Before(onset) {
+					ongoing[observance.isDST] = observance
+				}
+			} else {
+				for next, ok := observance.rule.Next(onset); ok; next, ok = observance.rule.Next(next) {
+					onsets = append(onsets, next)
+				}
+			}
+		}
+
+		for _, onset := range onsets {
+			transitions = append(transitions, icalTransition{when: onset.Unix(), observance: observance})
+		}
+	}
+
+	if len(transitions) == 0 {
+		return nil, icalError(tz.line, "STANDARD or DAYLIGHT is required")
+	}
+
+	sort.SliceStable(transitions, func(i, j int) bool {
+		return transitions[i].when < transitions[j].when
+	})
+
+	extend, err := icalZoneRule(tz, ongoing[false], ongoing[true])
+	if err != nil {
+		return
+	}
+
+	location, err = time.LoadLocationFromTZData(tzid, tzifData(transitions, extend))
+	if err != nil {
+		return nil, icalError(tz.line, "time zone %q: %v", tzid, err)
+	}
+	return
+}
+
+// icalZoneRule returns the POSIX TZ string of the ongoing rules, like
+// "<PST>8<PDT>,M3.2.0/2:00:00,M11.1.0/2:00:00".
+func icalZoneRule(tz *icalComponent, standard, daylight *icalObservance) (string, error) {
+	switch {
+	case daylight == nil && standard == nil:
+		return "", nil
+	case daylight == nil:
+		return posixZoneName(standard.name) + posixOffset(-standard.offset), nil
+	case standard == nil:
+		return "", icalError(tz.line, "a DAYLIGHT RRULE without a STANDARD RRULE is not supported")
+	}
+
+	res := posixZoneName(standard.name) + posixOffset(-standard.offset) + posixZoneName(daylight.name) + posixOffset(-daylight.offset)
+	for _, observance := range []*icalObservance{daylight, standard} {
+		date, err := posixDate(observance.rule)
+		if err != nil {
+			return "", icalError(tz.line, "%s RRULE %q: %v", observance.name, observance.rule.Rule(), err)
+		}
+		res += "," + date + "/" + posixOffset(observance.start)
+	}
+	return res, nil
+}
+
+// posixDate returns the "Mm.w.d" date of a yearly rule on the nth or the
+// last weekday of a month, like "FREQ=YEARLY;BYMONTH=11;BYDAY=1SU" or
+// "FREQ=YEARLY;BYMONTH=3;BYDAY=SU;BYMONTHDAY=8,9,10,11,12,13,14".
+func posixDate(r *RRule) (string, error) {
+	if r.freq != FrequencyYearly || r.interval != 1 || len(r.byMonth) != 1 || len(r.byDay) != 1 ||
+		len(r.byHour) > 0 || len(r.byMinute) > 0 || len(r.bySecond) > 0 {
+		return "", fmt.Errorf("only a yearly rule on a weekday of a month is supported")
+	}
+
+	week := r.byDay[0].n
+	if week == 0 && len(r.byMonthDay) == 7 {
+		days := append([]int(nil), r.byMonthDay...)
+		sort.Ints(days)
+		switch {
+		case days[0] == -7 && days[6] == -1:
+			week = -1
+		case days[6]-days[0] == 6 && days[0]%7 == 1:
+			week = days[0]/7 + 1
+		}
+	} else if len(r.byMonthDay) > 0 {
+		week = 0
+	}
+
+	switch {
+	case week == -1:
+		week = 5
+	case week < 1 || week > 4:
+		return "", fmt.Errorf("the weekday is not one of the first four or the last of the month")
+	}
+	return fmt.Sprintf("M%d.%d.%d", r.byMonth[0], week, r.byDay[0].weekday), nil
+}
+
+func posixZoneName(name string) string {
+	return "<" + strings.NewReplacer("<", "", ">", "").Replace(name) + ">"
+}
+
+// posixOffset formats seconds as "[-]h:mm:ss".
+func posixOffset(seconds int) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%d:%02d:%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+}
+
+// tzifData encodes the transitions and the POSIX TZ string extending them
+// in the version 2 format of RFC 8536, read by time.LoadLocationFromTZData.
+func tzifData(transitions []icalTransition, extend string) []byte {
+	var (
+		types   []tzifType
+		indexes = make([]int, len(transitions))
+		names   strings.Builder
+		offsets = map[string]int{}
+	)
+
+	for i, transition := range transitions {
+		t := tzifType{name: transition.observance.name, isDST: transition.observance.isDST, offset: transition.observance.offset}
+		indexes[i] = len(types)
+		for j := range types {
+			if types[j] == t {
+				indexes[i] = j
+				break
+			}
+		}
+
+		if indexes[i] == len(types) {
+			types = append(types, t)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	header := func(timeCount, typeCount, charCount int) {
+		buf.WriteString("TZif2")
+		buf.Write(make([]byte, 15))
+		for _, count := range []int{0, 0, 0, timeCount, typeCount, charCount} {
+			_ = binary.Write(buf, binary.BigEndian, uint32(count))
+		}
+	}
+
+	// The version 1 data is left empty, readers of version 2 skip it.
+	header(0, 1, 1)
+	buf.Write(make([]byte, 6+1))
+
+	for _, t := range types {
+		if _, isExists := offsets[t.name]; !isExists {
+			offsets[t.name] = names.Len()
+			names.WriteString(t.name + "\x00")
+		}
+	}
+
+	header(len(transitions), len(types), names.Len())
+	for _, transition := range transitions {
+		_ = binary.Write(buf, binary.BigEndian, transition.when)
+	}
+
+	for _, index := range indexes {
+		buf.WriteByte(byte(index))
+	}
+
+	for _, t := range types {
+		_ = binary.Write(buf, binary.BigEndian, int32(t.offset))
+		isDST := byte(0)
+		if t.isDST {
+			isDST = 1
+		}
+		buf.Write([]byte{isDST, byte(offsets[t.name])})
+	}
+
+	buf.WriteString(names.String())
+	buf.WriteString("\n" + extend + "\n")
+	return buf.Bytes()
+}
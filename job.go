@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusPaused  JobStatus = "paused"
+	JobStatusFailed  JobStatus = "failed"
+)
+
+// JobOption describes a job added with Add, AddDate, AddHandler or
+// AddDateHandler. Replace keeps the tags of the replaced job.
+type JobOption struct {
+	Tags []string
+}
+
+// JobInfo is a view of a job at the time it was read, RunCount counts the
+// fires of the job and LastRun is the latest retained run.
+type JobInfo struct {
+	Key       string
+	Handler   string
+	DateTime  time.Time
+	TimeZone  string
+	Remaining time.Duration
+	Status    JobStatus
+	RunCount  int
+	LastRun   *Run
+	Tags      []string
+	Trigger   string
+
+	// Recurrence is the trigger of a recurring job.
+	Recurrence Trigger
+}
+
+func jobTags(opts []JobOption) []string {
+	if len(opts) == 0 || len(opts[0].Tags) == 0 {
+		return nil
+	}
+
+	return append([]string(nil), opts[0].Tags...)
+}
+
+func (ds *detailScheduler) toJobInfo() *JobInfo {
+	info := &JobInfo{
+		Key:       ds.key,
+		Handler:   ds.handler,
+		DateTime:  ds.dateTime,
+		TimeZone:  ds.dateTime.Location().String(),
+		Remaining: time.Until(ds.dateTime),
+		Status:    JobStatusPending,
+		RunCount:  ds.runs,
+		Tags:      append([]string(nil), ds.tags...),
+		Trigger:   "once at " + ds.dateTime.Format(time.RFC3339),
+	}
+
+	if ds.trigger != nil {
+		info.Recurrence = ds.trigger
+		info.Trigger = ds.trigger.String()
+	}
+
+	switch {
+	case ds.running:
+		info.Status = JobStatusRunning
+	case ds.paused:
+		info.Status = JobStatusPaused
+		info.Remaining = ds.remaining
+	}
+
+	if info.Remaining < 0 {
+		info.Remaining = 0
+	}
+	return info
+}
+
+func (s *Scheduler) Get(key string) (info *JobInfo, err error) {
+	s.mutex.RLock()
+	ds, isExists := s.schedulers[key]
+	if !isExists {
+		s.mutex.RUnlock()
+		err = ErrKeyIsNotExists
+		return
+	}
+
+	info = ds.toJobInfo()
+	s.mutex.RUnlock()
+
+	s.withLastRun(info)
+	return
+}
+
+func (s *Scheduler) withLastRun(info *JobInfo) {
+	history := s.History(info.Key)
+	if len(history) == 0 {
+		return
+	}
+
+	info.LastRun = history[len(history)-1]
+	if info.Status == JobStatusPending && info.LastRun.Outcome == OutcomeFailure {
+		info.Status = JobStatusFailed
+	}
+}
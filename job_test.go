@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScheduler(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Get pending and paused key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler(Config{TimeZone: "Asia/Jakarta"})
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn, JobOption{Tags: []string{"billing"}}))
+
+			info, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, "add#1", info.Key)
+			assert.Equal(t, "Asia/Jakarta", info.TimeZone)
+			assert.Equal(t, JobStatusPending, info.Status)
+			assert.Equal(t, []string{"billing"}, info.Tags)
+			assert.Equal(t, 0, info.RunCount)
+			assert.Nil(t, info.LastRun)
+			assert.InDelta(t, time.Hour, info.Remaining, float64(time.Second))
+			assert.Equal(t, "once at "+info.DateTime.Format(time.RFC3339), info.Trigger)
+
+			assert.Nil(t, schedule.Pause("add#1"))
+			info, err = schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, JobStatusPaused, info.Status)
+
+			assert.Nil(t, schedule.Replace("add#1", time.Hour, fn))
+			info, err = schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"billing"}, info.Tags)
+
+			assert.Nil(t, schedule.ReplaceDateTime("add#1", time.Now().Add(time.Hour), fn, JobOption{Tags: []string{"report"}}))
+			info, err = schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"report"}, info.Tags)
+		})
+
+		t.Run("Get running key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			started, done := make(chan struct{}), make(chan struct{})
+			assert.Nil(t, schedule.Add("add#1", 0, func(ctx context.Context) {
+				close(started)
+				<-done
+			}))
+
+			<-started
+			info, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, JobStatusRunning, info.Status)
+			assert.Equal(t, 1, info.RunCount)
+			close(done)
+		})
+
+		t.Run("Get key whose last run failed", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			failure := errors.New("failure")
+			assert.Nil(t, schedule.Add("add#1", 0, func(ctx context.Context) {
+				Fail(ctx, failure)
+			}))
+			time.Sleep(50 * time.Millisecond)
+
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+			info, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, JobStatusFailed, info.Status)
+			assert.Equal(t, OutcomeFailure, info.LastRun.Outcome)
+			assert.Equal(t, failure, info.LastRun.Err)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Get unknown key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			info, err := schedule.Get("add#1")
+			assert.Nil(t, info)
+			assert.Equal(t, ErrKeyIsNotExists, err)
+		})
+	})
+}
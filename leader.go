@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLeaseName = "scheduler"
+	defaultLeaseTTL  = 15 * time.Second
+)
+
+// Locker grants name to a single owner until ttl elapses. Calling Lock again
+// with the same owner extends the lease.
+type Locker interface {
+	Lock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, name, owner string) error
+}
+
+// LeaderElector reports whether this process may dispatch jobs. Followers
+// keep their jobs but skip executing them.
+type LeaderElector interface {
+	IsLeader() bool
+}
+
+func defaultOwner() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+}
+
+type LeaseElectorConfig struct {
+	Name          string
+	Owner         string
+	TTL           time.Duration
+	RetryInterval time.Duration
+}
+
+type LeaseElector struct {
+	locker     Locker
+	config     LeaseElectorConfig
+	mutex      sync.RWMutex
+	leaseUntil time.Time
+	done       chan struct{}
+	stopped    chan struct{}
+	stopOnce   sync.Once
+}
+
+func NewLeaseElector(locker Locker, configs ...LeaseElectorConfig) *LeaseElector {
+	config := LeaseElectorConfig{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.Name == "" {
+		config.Name = defaultLeaseName
+	}
+
+	if config.Owner == "" {
+		config.Owner = defaultOwner()
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = defaultLeaseTTL
+	}
+
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = config.TTL / 3
+	}
+
+	elector := &LeaseElector{
+		locker:  locker,
+		config:  config,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go elector.run()
+	return elector
+}
+
+func (e *LeaseElector) run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(e.config.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		e.campaign()
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *LeaseElector) campaign() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.RetryInterval)
+	defer cancel()
+
+	start := time.Now()
+	isLocked, err := e.locker.Lock(ctx, e.config.Name, e.config.Owner, e.config.TTL)
+	if err != nil || !isLocked {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.leaseUntil = start.Add(e.config.TTL)
+}
+
+func (e *LeaseElector) IsLeader() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return time.Now().Before(e.leaseUntil)
+}
+
+func (e *LeaseElector) Stop() (err error) {
+	e.stopOnce.Do(func() {
+		close(e.done)
+		<-e.stopped
+
+		e.mutex.Lock()
+		e.leaseUntil = time.Time{}
+		e.mutex.Unlock()
+
+		err = e.locker.Unlock(context.Background(), e.config.Name, e.config.Owner)
+	})
+	return
+}
+
+type memoryLease struct {
+	owner      string
+	leaseUntil time.Time
+}
+
+type memoryLocker struct {
+	leases map[string]*memoryLease
+	mutex  sync.Mutex
+}
+
+func NewMemoryLocker() Locker {
+	return &memoryLocker{
+		leases: make(map[string]*memoryLease),
+	}
+}
+
+func (l *memoryLocker) Lock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	lease, isExists := l.leases[name]
+	if isExists && lease.owner != owner && now.Before(lease.leaseUntil) {
+		return false, nil
+	}
+
+	l.leases[name] = &memoryLease{
+		owner:      owner,
+		leaseUntil: now.Add(ttl),
+	}
+	return true, nil
+}
+
+func (l *memoryLocker) Unlock(ctx context.Context, name, owner string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if lease, isExists := l.leases[name]; isExists && lease.owner == owner {
+		delete(l.leases, name)
+	}
+	return nil
+}
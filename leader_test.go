@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderElector(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Only the leader fires jobs", func(t *testing.T) {
+			t.Parallel()
+			var (
+				counter int32
+				locker  = NewMemoryLocker()
+				config  = LeaseElectorConfig{TTL: 200 * time.Millisecond, RetryInterval: 10 * time.Millisecond}
+			)
+
+			schedules := make([]*Scheduler, 3)
+			for i := range schedules {
+				elector := NewLeaseElector(locker, config)
+				defer elector.Stop()
+				schedules[i] = NewScheduler(Config{LeaderElector: elector})
+				for j := 1; j <= 100; j++ {
+					err := schedules[i].Add(fmt.Sprintf("add#%d", j), 200*time.Millisecond, func(ctx context.Context) {
+						atomic.AddInt32(&counter, 1)
+					})
+					assert.Nil(t, err)
+				}
+			}
+
+			time.Sleep(400 * time.Millisecond)
+			assert.Equal(t, int32(100), atomic.LoadInt32(&counter))
+			for i := range schedules {
+				isExists, _ := schedules[i].read("add#1")
+				assert.Equal(t, isExists, false)
+			}
+		})
+
+		t.Run("Follower takes over when the leader stops", func(t *testing.T) {
+			t.Parallel()
+			var (
+				locker = NewMemoryLocker()
+				config = LeaseElectorConfig{TTL: time.Minute, RetryInterval: 10 * time.Millisecond}
+			)
+
+			leader := NewLeaseElector(locker, config)
+			assert.Eventually(t, leader.IsLeader, time.Second, 5*time.Millisecond)
+			follower := NewLeaseElector(locker, config)
+			defer follower.Stop()
+			time.Sleep(50 * time.Millisecond)
+			assert.False(t, follower.IsLeader())
+
+			assert.Nil(t, leader.Stop())
+			assert.False(t, leader.IsLeader())
+			assert.Eventually(t, follower.IsLeader, time.Second, 5*time.Millisecond)
+		})
+
+		t.Run("Follower takes over when the lease expires", func(t *testing.T) {
+			t.Parallel()
+			locker := NewMemoryLocker()
+			isLocked, err := locker.Lock(context.Background(), defaultLeaseName, "crashed", 100*time.Millisecond)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			follower := NewLeaseElector(locker, LeaseElectorConfig{TTL: time.Minute, RetryInterval: 10 * time.Millisecond})
+			defer follower.Stop()
+			assert.False(t, follower.IsLeader())
+			assert.Eventually(t, follower.IsLeader, time.Second, 5*time.Millisecond)
+		})
+	})
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+var (
+	defaultListColumns = []ListColumn{ListColumnNo, ListColumnKey, ListColumnDateTime}
+	jsonListColumns    = []ListColumn{ListColumnKey, ListColumnDateTime}
+
+	// listFields names the columns in the JSON and YAML converters.
+	listFields = map[ListColumn]string{
+		ListColumnNo:       "no",
+		ListColumnKey:      "key",
+		ListColumnDateTime: "date_time",
+		ListColumnHandler:  "handler",
+		ListColumnStatus:   "status",
+		ListColumnTags:     "tags",
+	}
+)
+
+// ListConfig selects the columns rendered by NewListConverter and their
+// order. An empty TimeFormat renders date times as time.Time.String does.
+type ListConfig struct {
+	Columns    []ListColumn
+	TimeFormat string
+}
+
+// NewListConverter returns the converter of listType, ListTypeDefault and
+// ListTypeJSON render like NewDefaultResponse and NewJsonResponse when no
+// column is selected. Every converter is a StreamConverter.
+func NewListConverter(listType ListType, configs ...ListConfig) ListConverter {
+	config := ListConfig{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	switch {
+	case len(config.Columns) > 0:
+	case listType == ListTypeJSON:
+		config.Columns = jsonListColumns
+	default:
+		config.Columns = defaultListColumns
+	}
+
+	switch listType {
+	case ListTypeJSON:
+		return &jsonResponse{config: config}
+	case ListTypeCSV:
+		return &csvResponse{config: config}
+	case ListTypeYAML:
+		return &yamlResponse{config: config}
+	case ListTypeMarkdown:
+		return &markdownResponse{config: config}
+	case ListTypeHTML:
+		return &htmlResponse{config: config}
+	case ListTypeICal:
+		return NewICalResponse()
+	default:
+		return &defaultResponse{config: config}
+	}
+}
+
+func (c ListColumn) value(no int, data *ResponseScheduler, timeFormat string) interface{} {
+	switch c {
+	case ListColumnNo:
+		return no
+	case ListColumnKey:
+		return data.Key
+	case ListColumnDateTime:
+		if timeFormat == "" {
+			return data.Time
+		}
+		return data.Time.Format(timeFormat)
+	case ListColumnHandler:
+		return data.Handler
+	case ListColumnStatus:
+		return string(data.Status)
+	case ListColumnTags:
+		return strings.Join(data.Tags, ",")
+	default:
+		return ""
+	}
+}
+
+func (config *ListConfig) header() []string {
+	header := make([]string, len(config.Columns))
+	for i, column := range config.Columns {
+		header[i] = string(column)
+	}
+	return header
+}
+
+func (config *ListConfig) row(no int, data *ResponseScheduler, row []string) []string {
+	for i, column := range config.Columns {
+		row[i] = fmt.Sprint(column.value(no, data, config.TimeFormat))
+	}
+	return row
+}
+
+type csvResponse struct {
+	config ListConfig
+}
+
+func (d *csvResponse) Convert(data []*ResponseScheduler) ([]byte, error) {
+	return convertStream(d, data)
+}
+
+func (d *csvResponse) Stream(w io.Writer, next ResponseIterator) (n int, err error) {
+	cw := &countWriter{w: w}
+	csvWriter := csv.NewWriter(cw)
+	_ = csvWriter.Write(d.config.header())
+
+	row := make([]string, len(d.config.Columns))
+	for no := 1; cw.err == nil; no++ {
+		res, ok := next()
+		if !ok {
+			break
+		}
+
+		_ = csvWriter.Write(d.config.row(no, res, row))
+	}
+
+	csvWriter.Flush()
+	if err = csvWriter.Error(); err == nil {
+		err = cw.err
+	}
+	return cw.n, err
+}
+
+type markdownResponse struct {
+	config ListConfig
+}
+
+func (d *markdownResponse) Convert(data []*ResponseScheduler) ([]byte, error) {
+	return convertStream(d, data)
+}
+
+func (d *markdownResponse) Stream(w io.Writer, next ResponseIterator) (n int, err error) {
+	cw := &countWriter{w: w}
+	writeRow := func(row []string) {
+		for _, cell := range row {
+			cw.WriteString("| " + strings.ReplaceAll(cell, "|", "\\|") + " ")
+		}
+		cw.WriteString("|\n")
+	}
+
+	writeRow(d.config.header())
+	separator := make([]string, len(d.config.Columns))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+
+	row := make([]string, len(d.config.Columns))
+	for no := 1; cw.err == nil; no++ {
+		res, ok := next()
+		if !ok {
+			break
+		}
+
+		writeRow(d.config.row(no, res, row))
+	}
+	return cw.n, cw.err
+}
+
+type htmlResponse struct {
+	config ListConfig
+}
+
+func (d *htmlResponse) Convert(data []*ResponseScheduler) ([]byte, error) {
+	return convertStream(d, data)
+}
+
+func (d *htmlResponse) Stream(w io.Writer, next ResponseIterator) (n int, err error) {
+	cw := &countWriter{w: w}
+	writeRow := func(tag string, row []string) {
+		cw.WriteString("  <tr>")
+		for _, cell := range row {
+			cw.WriteString("<" + tag + ">" + html.EscapeString(cell) + "</" + tag + ">")
+		}
+		cw.WriteString("</tr>\n")
+	}
+
+	cw.WriteString("<table>\n <thead>\n")
+	writeRow("th", d.config.header())
+	cw.WriteString(" </thead>\n <tbody>\n")
+
+	row := make([]string, len(d.config.Columns))
+	for no := 1; cw.err == nil; no++ {
+		res, ok := next()
+		if !ok {
+			break
+		}
+
+		writeRow("td", d.config.row(no, res, row))
+	}
+
+	cw.WriteString(" </tbody>\n</table>\n")
+	return cw.n, cw.err
+}
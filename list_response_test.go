@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestListConverterType(t *testing.T) {
+	dateTime := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+	data := []*ResponseScheduler{
+		{Key: "add#1", Time: dateTime, Handler: "handler", Status: JobStatusPending, Tags: []string{"billing", "daily"}},
+		{Key: "add#2", Time: dateTime.Add(time.Hour), Status: JobStatusPaused},
+	}
+	config := ListConfig{
+		Columns:    []ListColumn{ListColumnKey, ListColumnDateTime, ListColumnStatus, ListColumnTags},
+		TimeFormat: time.RFC3339,
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		res, err := NewListConverter(ListTypeCSV, config).Convert(data)
+		assert.Nil(t, err)
+		assert.Equal(t, "Key,Date Time,Status,Tags\n"+
+			"add#1,2022-08-01T10:00:00Z,pending,\"billing,daily\"\n"+
+			"add#2,2022-08-01T11:00:00Z,paused,\n", string(res))
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		res, err := NewListConverter(ListTypeMarkdown, config).Convert(data)
+		assert.Nil(t, err)
+		assert.Equal(t, "| Key | Date Time | Status | Tags |\n"+
+			"| --- | --- | --- | --- |\n"+
+			"| add#1 | 2022-08-01T10:00:00Z | pending | billing,daily |\n"+
+			"| add#2 | 2022-08-01T11:00:00Z | paused |  |\n", string(res))
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		res, err := NewListConverter(ListTypeHTML).Convert(data)
+		assert.Nil(t, err)
+		assert.Contains(t, string(res), "<th>Date Time</th>")
+		assert.Contains(t, string(res), "<td>add#2</td>")
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		res, err := NewListConverter(ListTypeYAML, config).Convert(data)
+		assert.Nil(t, err)
+
+		var actual []struct {
+			Key      string   `yaml:"key"`
+			DateTime string   `yaml:"date_time"`
+			Status   string   `yaml:"status"`
+			Tags     []string `yaml:"tags"`
+		}
+		assert.Nil(t, yaml.Unmarshal(res, &actual))
+		assert.Len(t, actual, 2)
+		assert.Equal(t, "add#1", actual[0].Key)
+		assert.Equal(t, "2022-08-01T10:00:00Z", actual[0].DateTime)
+		assert.Equal(t, []string{"billing", "daily"}, actual[0].Tags)
+		assert.Equal(t, "paused", actual[1].Status)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		res, err := NewListConverter(ListTypeJSON, config).Convert(data)
+		assert.Nil(t, err)
+		assert.Equal(t, `[{"key":"add#1","date_time":"2022-08-01T10:00:00Z","status":"pending","tags":["billing","daily"]},`+
+			`{"key":"add#2","date_time":"2022-08-01T11:00:00Z","status":"paused","tags":null}]`, string(res))
+
+		res, err = NewListConverter(ListTypeJSON).Convert(data)
+		assert.Nil(t, err)
+		expected, err := NewJsonResponse().Convert(data)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		res, err := NewListConverter(ListTypeDefault, config).Convert(data)
+		assert.Nil(t, err)
+		assert.Equal(t, "+-------+----------------------+---------+---------------+\n"+
+			"| KEY   | DATE TIME            | STATUS  | TAGS          |\n"+
+			"+-------+----------------------+---------+---------------+\n"+
+			"| add#1 | 2022-08-01T10:00:00Z | pending | billing,daily |\n"+
+			"| add#2 | 2022-08-01T11:00:00Z | paused  |               |\n"+
+			"+-------+----------------------+---------+---------------+", string(res))
+
+		res, err = NewListConverter(ListTypeDefault).Convert(data)
+		assert.Nil(t, err)
+		expected, err := NewDefaultResponse().Convert(data)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("Repeated convert", func(t *testing.T) {
+		lc := NewListConverter(ListTypeCSV)
+		first, err := lc.Convert(data)
+		assert.Nil(t, err)
+		second, err := lc.Convert(data)
+		assert.Nil(t, err)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("List with list type", func(t *testing.T) {
+		schedule := NewScheduler()
+		assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+
+		buf := &bytes.Buffer{}
+		_, err := schedule.List(buf, NewListConverter(ListTypeCSV, ListConfig{Columns: []ListColumn{ListColumnNo, ListColumnKey}}))
+		assert.Nil(t, err)
+		assert.Equal(t, "No.,Key\n1,add#1\n", buf.String())
+	})
+}
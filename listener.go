@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"time"
+)
+
+type EventType int
+
+const (
+	EventTypeScheduled EventType = iota + 1
+	EventTypeRescheduled
+	EventTypeReplaced
+	EventTypeCancelled
+	EventTypeStarted
+	EventTypeSucceeded
+	EventTypeFailed
+	EventTypeSkipped
+	EventTypeRemoved
+)
+
+var eventTypeNames = [...]string{
+	EventTypeScheduled:   "scheduled",
+	EventTypeRescheduled: "rescheduled",
+	EventTypeReplaced:    "replaced",
+	EventTypeCancelled:   "cancelled",
+	EventTypeStarted:     "started",
+	EventTypeSucceeded:   "succeeded",
+	EventTypeFailed:      "failed",
+	EventTypeSkipped:     "skipped",
+	EventTypeRemoved:     "removed",
+}
+
+func (t EventType) String() string {
+	if t <= 0 || int(t) >= len(eventTypeNames) {
+		return "unknown"
+	}
+	return eventTypeNames[t]
+}
+
+type Event struct {
+	Type             EventType
+	Key              string
+	Handler          string
+	DateTime         time.Time
+	PreviousDateTime time.Time
+	StartTime        time.Time
+	EndTime          time.Time
+	Attempt          int
+	Err              error
+	Output           string
+}
+
+// Listener is called outside of the scheduler lock, so it may call back into
+// the scheduler. OnSkipped and OnFailure carry the reason in Event.Err,
+// OnSuccess and OnFailure the output set by the job with SetOutput.
+type Listener interface {
+	OnScheduled(event Event)
+	OnRescheduled(event Event)
+	OnReplaced(event Event)
+	OnCancelled(event Event)
+	OnStart(event Event)
+	OnSuccess(event Event)
+	OnFailure(event Event)
+	OnSkipped(event Event)
+	OnRemoved(event Event)
+}
+
+type NopListener struct{}
+
+func (NopListener) OnScheduled(event Event)   {}
+func (NopListener) OnRescheduled(event Event) {}
+func (NopListener) OnReplaced(event Event)    {}
+func (NopListener) OnCancelled(event Event)   {}
+func (NopListener) OnStart(event Event)       {}
+func (NopListener) OnSuccess(event Event)     {}
+func (NopListener) OnFailure(event Event)     {}
+func (NopListener) OnSkipped(event Event)     {}
+func (NopListener) OnRemoved(event Event)     {}
+
+func (s *Scheduler) AddListener(l Listener) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.listeners = append(s.listeners, l)
+}
+
+func (s *Scheduler) emit(eventType EventType, event Event) {
+	s.mutex.RLock()
+	listeners := s.listeners
+	s.mutex.RUnlock()
+
+	event.Type = eventType
+	for i := 0; i < len(listeners); i++ {
+		func() {
+			defer func() {
+				_ = recover()
+			}()
+
+			dispatch(listeners[i], event)
+		}()
+	}
+}
+
+func dispatch(l Listener, event Event) {
+	switch event.Type {
+	case EventTypeScheduled:
+		l.OnScheduled(event)
+	case EventTypeRescheduled:
+		l.OnRescheduled(event)
+	case EventTypeReplaced:
+		l.OnReplaced(event)
+	case EventTypeCancelled:
+		l.OnCancelled(event)
+	case EventTypeStarted:
+		l.OnStart(event)
+	case EventTypeSucceeded:
+		l.OnSuccess(event)
+	case EventTypeFailed:
+		l.OnFailure(event)
+	case EventTypeSkipped:
+		l.OnSkipped(event)
+	case EventTypeRemoved:
+		l.OnRemoved(event)
+	}
+}
@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordListener struct {
+	mutex  sync.Mutex
+	events map[string][]Event
+	onCall func(name string, event Event)
+}
+
+func newRecordListener() *recordListener {
+	return &recordListener{events: make(map[string][]Event)}
+}
+
+func (r *recordListener) record(name string, event Event) {
+	r.mutex.Lock()
+	r.events[name] = append(r.events[name], event)
+	onCall := r.onCall
+	r.mutex.Unlock()
+
+	if onCall != nil {
+		onCall(name, event)
+	}
+}
+
+func (r *recordListener) get(name string) []Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]Event(nil), r.events[name]...)
+}
+
+func (r *recordListener) OnScheduled(event Event)   { r.record("scheduled", event) }
+func (r *recordListener) OnRescheduled(event Event) { r.record("rescheduled", event) }
+func (r *recordListener) OnReplaced(event Event)    { r.record("replaced", event) }
+func (r *recordListener) OnCancelled(event Event)   { r.record("cancelled", event) }
+func (r *recordListener) OnStart(event Event)       { r.record("start", event) }
+func (r *recordListener) OnSuccess(event Event)     { r.record("success", event) }
+func (r *recordListener) OnFailure(event Event)     { r.record("failure", event) }
+func (r *recordListener) OnSkipped(event Event)     { r.record("skipped", event) }
+func (r *recordListener) OnRemoved(event Event)     { r.record("removed", event) }
+
+func TestListener(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Lifecycle of jobs", func(t *testing.T) {
+			t.Parallel()
+			listener := newRecordListener()
+			schedule := NewScheduler(Config{Listeners: []Listener{listener}})
+			failure := errors.New("failure")
+
+			assert.Nil(t, schedule.Add("add#1", 50*time.Millisecond, fn))
+			assert.Nil(t, schedule.Add("add#2", 50*time.Millisecond, func(ctx context.Context) {
+				Fail(ctx, failure)
+			}))
+			assert.Nil(t, schedule.Add("add#3", time.Hour, fn))
+			assert.Nil(t, schedule.Add("add#4", time.Hour, fn))
+			assert.Nil(t, schedule.Reschedule("add#3", 2*time.Hour))
+			assert.Nil(t, schedule.Replace("add#4", 2*time.Hour, fn))
+			assert.Nil(t, schedule.Cancel("add#3"))
+			time.Sleep(150 * time.Millisecond)
+
+			assert.Len(t, listener.get("scheduled"), 4)
+			assert.Len(t, listener.get("rescheduled"), 1)
+			rescheduled := listener.get("rescheduled")[0]
+			assert.Equal(t, "add#3", rescheduled.Key)
+			assert.WithinDuration(t, rescheduled.PreviousDateTime.Add(time.Hour), rescheduled.DateTime, time.Second)
+			assert.Len(t, listener.get("replaced"), 1)
+			assert.Equal(t, "add#4", listener.get("replaced")[0].Key)
+			assert.Len(t, listener.get("cancelled"), 1)
+			assert.Len(t, listener.get("start"), 2)
+			assert.Len(t, listener.get("removed"), 2)
+
+			success := listener.get("success")
+			assert.Len(t, success, 1)
+			assert.Equal(t, "add#1", success[0].Key)
+			assert.False(t, success[0].StartTime.Before(success[0].DateTime))
+			assert.False(t, success[0].EndTime.Before(success[0].StartTime))
+
+			failed := listener.get("failure")
+			assert.Len(t, failed, 1)
+			assert.Equal(t, "add#2", failed[0].Key)
+			assert.Equal(t, failure, failed[0].Err)
+		})
+
+		t.Run("Skipped by the execution locker", func(t *testing.T) {
+			t.Parallel()
+			listener := newRecordListener()
+			locker := NewMemoryLocker()
+			dateTime := time.Now().UTC().Add(50 * time.Millisecond)
+			for i := 0; i < 2; i++ {
+				schedule := NewScheduler(Config{ExecutionLocker: locker})
+				schedule.AddListener(listener)
+				assert.Nil(t, schedule.AddDate("add#1", dateTime, fn))
+			}
+
+			time.Sleep(150 * time.Millisecond)
+			assert.Len(t, listener.get("success"), 1)
+			skipped := listener.get("skipped")
+			assert.Len(t, skipped, 1)
+			assert.ErrorIs(t, skipped[0].Err, ErrExecutionIsLocked)
+		})
+
+		t.Run("Listener calls back into the scheduler", func(t *testing.T) {
+			t.Parallel()
+			listener := newRecordListener()
+			schedule := NewScheduler(Config{Listeners: []Listener{listener}})
+			listener.onCall = func(name string, event Event) {
+				switch name {
+				case "scheduled":
+					_ = schedule.Reschedule(event.Key, 50*time.Millisecond)
+				case "start":
+					_ = schedule.Cancel(event.Key)
+				}
+				schedule.List(&nopWriter{})
+			}
+
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+			time.Sleep(150 * time.Millisecond)
+			assert.Len(t, listener.get("rescheduled"), 1)
+			assert.Len(t, listener.get("cancelled"), 1)
+			assert.Len(t, listener.get("success"), 1)
+			assert.Len(t, listener.get("removed"), 0)
+			isExists, _ := schedule.read("add#1")
+			assert.Equal(t, isExists, false)
+		})
+
+		t.Run("Panic in listener is recovered", func(t *testing.T) {
+			t.Parallel()
+			listener := newRecordListener()
+			listener.onCall = func(name string, event Event) {
+				panic(name)
+			}
+			schedule := NewScheduler(Config{Listeners: []Listener{listener, NopListener{}}})
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, fn))
+			time.Sleep(50 * time.Millisecond)
+			assert.Len(t, listener.get("removed"), 1)
+		})
+	})
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultMisfireThreshold = time.Second
+)
+
+type loggerKey struct{}
+
+func (s *Scheduler) log(level slog.Level, msg string, attrs ...any) {
+	if s.config.Logger != nil {
+		s.config.Logger.Log(context.Background(), level, msg, attrs...)
+	}
+}
+
+type logListener struct {
+	logger           *slog.Logger
+	misfireThreshold time.Duration
+}
+
+func newLogListener(logger *slog.Logger, misfireThreshold time.Duration) *logListener {
+	if misfireThreshold <= 0 {
+		misfireThreshold = defaultMisfireThreshold
+	}
+
+	return &logListener{
+		logger:           logger,
+		misfireThreshold: misfireThreshold,
+	}
+}
+
+func eventAttrs(event Event, attrs ...any) []any {
+	res := []any{slog.String("key", event.Key)}
+	if event.Handler != "" {
+		res = append(res, slog.String("handler", event.Handler))
+	}
+
+	if !event.DateTime.IsZero() {
+		res = append(res, slog.Time("date_time", event.DateTime))
+	}
+	return append(res, attrs...)
+}
+
+func (l *logListener) OnScheduled(event Event) {
+	l.logger.Debug("job scheduled", eventAttrs(event)...)
+}
+
+func (l *logListener) OnRescheduled(event Event) {
+	l.logger.Debug("job rescheduled", eventAttrs(event, slog.Time("previous_date_time", event.PreviousDateTime))...)
+}
+
+func (l *logListener) OnReplaced(event Event) {
+	l.logger.Debug("job replaced", eventAttrs(event, slog.Time("previous_date_time", event.PreviousDateTime))...)
+}
+
+func (l *logListener) OnCancelled(event Event) {
+	l.logger.Info("job cancelled", eventAttrs(event)...)
+}
+
+func (l *logListener) OnStart(event Event) {
+	lateness := event.StartTime.Sub(event.DateTime)
+	attrs := eventAttrs(event, slog.Int("attempt", event.Attempt), slog.Duration("lateness", lateness))
+	if lateness > l.misfireThreshold {
+		l.logger.Warn("job misfired", attrs...)
+		return
+	}
+
+	l.logger.Info("job fired", attrs...)
+}
+
+func (l *logListener) OnSuccess(event Event) {
+	l.logger.Debug("job succeeded", eventAttrs(event,
+		slog.Int("attempt", event.Attempt),
+		slog.Duration("duration", event.EndTime.Sub(event.StartTime)),
+	)...)
+}
+
+func (l *logListener) OnFailure(event Event) {
+	msg := "job failed"
+	if errors.Is(event.Err, ErrJobPanic) {
+		msg = "job panicked"
+	}
+
+	l.logger.Error(msg, eventAttrs(event,
+		slog.Int("attempt", event.Attempt),
+		slog.Duration("duration", event.EndTime.Sub(event.StartTime)),
+		slog.Any("error", event.Err),
+	)...)
+}
+
+func (l *logListener) OnSkipped(event Event) {
+	l.logger.Info("job skipped", eventAttrs(event, slog.Any("reason", event.Err))...)
+}
+
+func (l *logListener) OnRemoved(event Event) {
+	l.logger.Debug("job removed", eventAttrs(event)...)
+}
+
+func (l *logListener) middleware(next FnScheduler) FnScheduler {
+	return func(ctx context.Context) {
+		exec, _ := ExecutionFromContext(ctx)
+		logger := l.logger.With(slog.String("key", exec.Key), slog.Int("attempt", exec.Attempt))
+		next(context.WithValue(ctx, loggerKey{}, logger))
+	}
+}
+
+// LoggerFromContext returns the scheduler logger carrying the key and attempt
+// of the running job, or slog.Default outside of a job.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, isExists := ctx.Value(loggerKey{}).(*slog.Logger); isExists {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler adds the key and attempt of the running job to records
+// logged with a job context, e.g. through slog.InfoContext.
+func NewContextHandler(h slog.Handler) slog.Handler {
+	return &contextHandler{Handler: h}
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if exec, isExists := ExecutionFromContext(ctx); isExists {
+		record.AddAttrs(slog.String("key", exec.Key), slog.Int("attempt", exec.Attempt))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) records(t *testing.T) (res []map[string]any) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		record := make(map[string]any)
+		assert.Nil(t, json.Unmarshal([]byte(line), &record))
+		res = append(res, record)
+	}
+	return
+}
+
+func findRecord(records []map[string]any, msg, key string) map[string]any {
+	for _, record := range records {
+		if record["msg"] == msg && record["key"] == key {
+			return record
+		}
+	}
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Log scheduler decisions", func(t *testing.T) {
+			t.Parallel()
+			buf := &syncBuffer{}
+			logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			schedule := NewScheduler(Config{Logger: logger, MisfireThreshold: time.Nanosecond})
+
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, fn))
+			assert.Nil(t, schedule.Add("add#2", 10*time.Millisecond, func(ctx context.Context) {
+				Fail(ctx, errors.New("failure"))
+			}))
+			assert.Nil(t, schedule.Add("add#3", 10*time.Millisecond, func(ctx context.Context) {
+				panic("failure")
+			}))
+			assert.Nil(t, schedule.Add("add#4", time.Hour, fn))
+			assert.Nil(t, schedule.Reschedule("add#4", 2*time.Hour))
+			assert.Nil(t, schedule.Cancel("add#4"))
+			time.Sleep(50 * time.Millisecond)
+			schedule.Stop()
+
+			records := buf.records(t)
+			assert.NotNil(t, findRecord(records, "job scheduled", "add#1"))
+			assert.NotNil(t, findRecord(records, "job rescheduled", "add#4"))
+			assert.NotNil(t, findRecord(records, "job cancelled", "add#4"))
+			assert.Nil(t, findRecord(records, "job fired", "add#1"))
+			assert.NotNil(t, findRecord(records, "job misfired", "add#1"))
+			assert.NotNil(t, findRecord(records, "job succeeded", "add#1"))
+
+			failed := findRecord(records, "job failed", "add#2")
+			assert.NotNil(t, failed)
+			assert.Equal(t, "ERROR", failed["level"])
+			assert.Equal(t, "failure", failed["error"])
+			assert.NotNil(t, findRecord(records, "job panicked", "add#3"))
+
+			stopped := records[len(records)-1]
+			assert.Equal(t, "scheduler stopped", stopped["msg"])
+			assert.Equal(t, float64(0), stopped["pending"])
+		})
+
+		t.Run("Job fired on time", func(t *testing.T) {
+			t.Parallel()
+			buf := &syncBuffer{}
+			schedule := NewScheduler(Config{Logger: slog.New(slog.NewJSONHandler(buf, nil))})
+			done := make(chan struct{})
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {
+				close(done)
+			}))
+			<-done
+			schedule.Stop()
+
+			records := buf.records(t)
+			assert.NotNil(t, findRecord(records, "job fired", "add#1"))
+			assert.Nil(t, findRecord(records, "job misfired", "add#1"))
+		})
+
+		t.Run("Job logs carry the key and attempt", func(t *testing.T) {
+			t.Parallel()
+			buf := &syncBuffer{}
+			jobBuf := &syncBuffer{}
+			logger := slog.New(slog.NewJSONHandler(buf, nil))
+			jobLogger := slog.New(NewContextHandler(slog.NewJSONHandler(jobBuf, nil)))
+			done := make(chan struct{})
+			schedule := NewScheduler(Config{Logger: logger})
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {
+				LoggerFromContext(ctx).Info("from scheduler logger")
+				jobLogger.InfoContext(ctx, "from job logger")
+				close(done)
+			}))
+			<-done
+
+			assert.NotNil(t, findRecord(buf.records(t), "from scheduler logger", "add#1"))
+			record := findRecord(jobBuf.records(t), "from job logger", "add#1")
+			assert.NotNil(t, record)
+			assert.Equal(t, float64(1), record["attempt"])
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Logger outside of a job", func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, slog.Default(), LoggerFromContext(context.Background()))
+		})
+	})
+}
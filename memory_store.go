@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryStoreJob struct {
+	job        *StoreJob
+	dueTime    time.Time
+	owner      string
+	leaseUntil time.Time
+}
+
+type memoryStore struct {
+	jobs  map[string]*memoryStoreJob
+	mutex sync.Mutex
+}
+
+func NewMemoryStore() LeaseStore {
+	return &memoryStore{
+		jobs: make(map[string]*memoryStoreJob),
+	}
+}
+
+func (m *memoryStore) Add(ctx context.Context, job *StoreJob) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, isExists := m.jobs[job.Key]; isExists {
+		return ErrKeyIsExists
+	}
+
+	jobCopy := *job
+	m.jobs[job.Key] = &memoryStoreJob{
+		job:     &jobCopy,
+		dueTime: job.DateTime,
+	}
+	return nil
+}
+
+func (m *memoryStore) Reschedule(ctx context.Context, key string, dateTime time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	msj, isExists := m.jobs[key]
+	if !isExists {
+		return ErrKeyIsNotExists
+	}
+
+	msj.job.DateTime = dateTime
+	msj.dueTime = dateTime
+	msj.owner = ""
+	return nil
+}
+
+func (m *memoryStore) Cancel(ctx context.Context, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, isExists := m.jobs[key]; !isExists {
+		return ErrKeyIsNotExists
+	}
+
+	delete(m.jobs, key)
+	return nil
+}
+
+func (m *memoryStore) due(now time.Time, limit int) (res []*memoryStoreJob) {
+	for _, msj := range m.jobs {
+		if msj.owner != "" && now.Before(msj.leaseUntil) {
+			continue
+		}
+
+		if !msj.dueTime.After(now) {
+			res = append(res, msj)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].dueTime.Before(res[j].dueTime)
+	})
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return
+}
+
+func (m *memoryStore) Claim(ctx context.Context, now time.Time, limit int) (jobs []*StoreJob, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, msj := range m.due(now, limit) {
+		delete(m.jobs, msj.job.Key)
+		jobs = append(jobs, msj.job)
+	}
+	return
+}
+
+func (m *memoryStore) Lease(ctx context.Context, owner string, now time.Time, ttl time.Duration, limit int) (jobs []*StoreJob, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, msj := range m.due(now, limit) {
+		msj.owner = owner
+		msj.leaseUntil = now.Add(ttl)
+		msj.job.Attempt++
+		jobCopy := *msj.job
+		jobs = append(jobs, &jobCopy)
+	}
+	return
+}
+
+func (m *memoryStore) held(key, owner string) (msj *memoryStoreJob, err error) {
+	msj, isExists := m.jobs[key]
+	if !isExists || msj.owner != owner {
+		err = ErrLeaseIsNotHeld
+	}
+	return
+}
+
+func (m *memoryStore) Ack(ctx context.Context, key, owner string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, err := m.held(key, owner); err != nil {
+		return err
+	}
+
+	delete(m.jobs, key)
+	return nil
+}
+
+func (m *memoryStore) Release(ctx context.Context, key, owner string, dateTime time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	msj, err := m.held(key, owner)
+	if err != nil {
+		return err
+	}
+
+	msj.owner = ""
+	msj.dueTime = dateTime
+	return nil
+}
@@ -3,6 +3,13 @@ package scheduler
 import "time"
 
 type ResponseScheduler struct {
-	Key  string
-	Time time.Time
+	Key     string
+	Time    time.Time
+	Handler string
+	Status  JobStatus
+	Tags    []string
+	History []*Run
+
+	// Recurrence is the trigger of a recurring job.
+	Recurrence Trigger
 }
@@ -0,0 +1,147 @@
+package oteltracer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/sodri126/go-simple-scheduler/oteltracer"
+	defaultSpanName     = "scheduler.execute"
+
+	AttributeKey      = attribute.Key("scheduler.key")
+	AttributeHandler  = attribute.Key("scheduler.handler")
+	AttributeDateTime = attribute.Key("scheduler.date_time")
+	AttributeLateness = attribute.Key("scheduler.lateness_ms")
+	AttributeAttempt  = attribute.Key("scheduler.attempt")
+	AttributeOutcome  = attribute.Key("scheduler.outcome")
+
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+type Config struct {
+	TracerProvider trace.TracerProvider
+	SpanName       func(exec scheduler.Execution) string
+}
+
+type Tracer struct {
+	scheduler.NopListener
+
+	tracer trace.Tracer
+	config Config
+	links  map[string]trace.Link
+	mutex  sync.Mutex
+}
+
+func NewTracer(s *scheduler.Scheduler, configs ...Config) *Tracer {
+	config := Config{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+
+	if config.SpanName == nil {
+		config.SpanName = func(exec scheduler.Execution) string {
+			return defaultSpanName
+		}
+	}
+
+	t := &Tracer{
+		tracer: config.TracerProvider.Tracer(instrumentationName),
+		config: config,
+		links:  make(map[string]trace.Link),
+	}
+
+	s.Use(t.middleware)
+	s.AddListener(t)
+	return t
+}
+
+// Link links the next execution of key to the span active in ctx, call it
+// next to Add.
+func (t *Tracer) Link(ctx context.Context, key string) {
+	link := trace.LinkFromContext(ctx)
+	if !link.SpanContext.IsValid() {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.links[key] = link
+}
+
+func (t *Tracer) takeLink(key string) (link trace.Link, isExists bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	link, isExists = t.links[key]
+	delete(t.links, key)
+	return
+}
+
+func (t *Tracer) OnCancelled(event scheduler.Event) {
+	t.takeLink(event.Key)
+}
+
+func (t *Tracer) OnSkipped(event scheduler.Event) {
+	t.takeLink(event.Key)
+}
+
+func (t *Tracer) middleware(next scheduler.FnScheduler) scheduler.FnScheduler {
+	return func(ctx context.Context) {
+		exec, _ := scheduler.ExecutionFromContext(ctx)
+		opts := []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(
+				AttributeKey.String(exec.Key),
+				AttributeHandler.String(exec.Handler),
+				AttributeDateTime.String(exec.DateTime.Format(time.RFC3339Nano)),
+				AttributeLateness.Int64(exec.StartTime.Sub(exec.DateTime).Milliseconds()),
+				AttributeAttempt.Int(exec.Attempt),
+			),
+		}
+
+		if link, isExists := t.takeLink(exec.Key); isExists {
+			opts = append(opts, trace.WithLinks(link))
+		}
+
+		ctx, span := t.tracer.Start(ctx, t.config.SpanName(exec), opts...)
+		defer func() {
+			if r := recover(); r != nil {
+				t.end(span, fmt.Errorf("%w: %v", scheduler.ErrJobPanic, r))
+				panic(r)
+			}
+
+			t.end(span, scheduler.ErrFromContext(ctx))
+		}()
+
+		next(ctx)
+	}
+}
+
+func (t *Tracer) end(span trace.Span, err error) {
+	defer span.End()
+
+	if err == nil {
+		span.SetAttributes(AttributeOutcome.String(OutcomeSuccess))
+		span.SetStatus(codes.Ok, "")
+		return
+	}
+
+	span.SetAttributes(AttributeOutcome.String(OutcomeFailure))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
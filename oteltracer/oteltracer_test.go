@@ -0,0 +1,106 @@
+package oteltracer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracer(t *testing.T) (*scheduler.Scheduler, *Tracer, *tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+
+	schedule := scheduler.NewScheduler()
+	return schedule, NewTracer(schedule, Config{TracerProvider: provider}), exporter, provider
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	res := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		res[kv.Key] = kv.Value
+	}
+	return res
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("Span of a successful execution is parent of the job spans", func(t *testing.T) {
+		schedule, _, exporter, provider := newTracer(t)
+		done := make(chan struct{})
+		err := schedule.Add("add#1", 20*time.Millisecond, func(ctx context.Context) {
+			_, span := provider.Tracer("job").Start(ctx, "job")
+			span.End()
+			close(done)
+		})
+		assert.Nil(t, err)
+		<-done
+		time.Sleep(10 * time.Millisecond)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		job, execution := spans[0], spans[1]
+		assert.Equal(t, defaultSpanName, execution.Name)
+		assert.Equal(t, execution.SpanContext.SpanID(), job.Parent.SpanID())
+		assert.Equal(t, codes.Ok, execution.Status.Code)
+
+		attrs := attributes(execution)
+		assert.Equal(t, "add#1", attrs[AttributeKey].AsString())
+		assert.Equal(t, int64(1), attrs[AttributeAttempt].AsInt64())
+		assert.Equal(t, OutcomeSuccess, attrs[AttributeOutcome].AsString())
+		assert.GreaterOrEqual(t, attrs[AttributeLateness].AsInt64(), int64(0))
+		assert.NotEmpty(t, attrs[AttributeDateTime].AsString())
+	})
+
+	t.Run("Span of failed executions", func(t *testing.T) {
+		schedule, _, exporter, _ := newTracer(t)
+		assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {
+			scheduler.Fail(ctx, errors.New("failure"))
+		}))
+		assert.Nil(t, schedule.Add("add#2", 10*time.Millisecond, func(ctx context.Context) {
+			panic("failure")
+		}))
+		time.Sleep(50 * time.Millisecond)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		for _, span := range spans {
+			assert.Equal(t, codes.Error, span.Status.Code)
+			assert.Equal(t, OutcomeFailure, attributes(span)[AttributeOutcome].AsString())
+			assert.Len(t, span.Events, 1)
+		}
+	})
+
+	t.Run("Link to the span active when the job was added", func(t *testing.T) {
+		schedule, tracer, exporter, provider := newTracer(t)
+		ctx, parent := provider.Tracer("caller").Start(context.Background(), "caller")
+		tracer.Link(ctx, "add#1")
+		assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {}))
+		parent.End()
+
+		tracer.Link(ctx, "add#2")
+		assert.Nil(t, schedule.Add("add#2", time.Hour, func(ctx context.Context) {}))
+		assert.Nil(t, schedule.Cancel("add#2"))
+		tracer.Link(context.Background(), "add#3")
+		time.Sleep(50 * time.Millisecond)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		execution := spans[1]
+		assert.Len(t, execution.Links, 1)
+		assert.Equal(t, parent.SpanContext().SpanID(), execution.Links[0].SpanContext.SpanID())
+		assert.False(t, execution.Parent.IsValid())
+		assert.Equal(t, trace.SpanKindInternal, execution.SpanKind)
+		assert.Len(t, tracer.links, 0)
+	})
+}
@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// Pause stops the timer of the job of key until it is resumed. A recurring
+// job paused while it runs is paused at its next date time, a job which
+// does not recur returns ErrKeyIsRunning once its timer fired.
+func (s *Scheduler) Pause(key string) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ds, isExists := s.schedulers[key]
+	if !isExists {
+		err = ErrKeyIsNotExists
+		return
+	}
+
+	if ds.paused {
+		err = ErrKeyIsPaused
+		return
+	}
+
+	if !ds.timer.Stop() {
+		if ds.trigger == nil {
+			err = ErrKeyIsRunning
+			return
+		}
+		ds.pauseOnRearm = true
+	}
+
+	ds.paused = true
+	ds.remaining = time.Until(ds.dateTime)
+	if ds.remaining < 0 {
+		ds.remaining = 0
+	}
+	return
+}
+
+func (s *Scheduler) Resume(key string) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ds, isExists := s.schedulers[key]
+	if !isExists {
+		err = ErrKeyIsNotExists
+		return
+	}
+
+	if !ds.paused {
+		err = ErrKeyIsNotPaused
+		return
+	}
+
+	ds.paused = false
+	if ds.pauseOnRearm {
+		// The run is not rearmed yet, rearm resets the timer.
+		ds.pauseOnRearm = false
+		return
+	}
+
+	s.schedulersIndex.Move(ds, s.fromDurationToDateTime(ds.remaining))
+	ds.timer.Reset(ds.remaining)
+	return
+}
+
+func (s *Scheduler) Pending() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.schedulers) - s.paused()
+}
+
+func (s *Scheduler) Running() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.running
+}
+
+func (s *Scheduler) Paused() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.paused()
+}
+
+func (s *Scheduler) paused() (total int) {
+	for _, ds := range s.schedulers {
+		if ds.paused {
+			total++
+		}
+	}
+	return
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPauseScheduler(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Pause and resume key", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			schedule := NewScheduler()
+			err := schedule.Add("add#1", 100*time.Millisecond, func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			})
+			assert.Nil(t, err)
+			assert.Nil(t, schedule.Add("add#2", time.Hour, fn))
+			assert.Nil(t, schedule.Pause("add#1"))
+			assert.Equal(t, 1, schedule.Paused())
+			assert.Equal(t, 1, schedule.Pending())
+
+			time.Sleep(200 * time.Millisecond)
+			assert.Equal(t, int32(0), atomic.LoadInt32(&counter))
+			isExists, _ := schedule.read("add#1")
+			assert.Equal(t, isExists, true)
+
+			assert.Nil(t, schedule.Resume("add#1"))
+			assert.Equal(t, 0, schedule.Paused())
+			time.Sleep(200 * time.Millisecond)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+			assert.Equal(t, 1, schedule.Pending())
+		})
+
+		t.Run("Reschedule paused key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+			assert.Nil(t, schedule.Pause("add#1"))
+			assert.Nil(t, schedule.Reschedule("add#1", 50*time.Millisecond))
+			assert.Equal(t, 0, schedule.Paused())
+			time.Sleep(100 * time.Millisecond)
+			isExists, _ := schedule.read("add#1")
+			assert.Equal(t, isExists, false)
+		})
+
+		t.Run("Pause running recurring key", func(t *testing.T) {
+			t.Parallel()
+			var (
+				counter int32
+				started = make(chan struct{}, 1)
+				release = make(chan struct{})
+			)
+			schedule := NewScheduler()
+			err := schedule.AddTrigger("add#1", NewInterval(time.Now().Add(50*time.Millisecond), 50*time.Millisecond), func(ctx context.Context) {
+				if atomic.AddInt32(&counter, 1) == 1 {
+					started <- struct{}{}
+					<-release
+				}
+			})
+			assert.Nil(t, err)
+
+			<-started
+			assert.Nil(t, schedule.Pause("add#1"))
+			assert.Equal(t, ErrKeyIsPaused, schedule.Pause("add#1"))
+			close(release)
+
+			time.Sleep(200 * time.Millisecond)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+			info, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, JobStatusPaused, info.Status)
+			assert.True(t, info.Remaining <= 50*time.Millisecond)
+
+			assert.Nil(t, schedule.Resume("add#1"))
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&counter) > 1
+			}, time.Second, 10*time.Millisecond)
+		})
+
+		t.Run("Resume recurring key before its run ends", func(t *testing.T) {
+			t.Parallel()
+			var (
+				counter int32
+				started = make(chan struct{}, 1)
+				release = make(chan struct{})
+			)
+			schedule := NewScheduler()
+			err := schedule.AddTrigger("add#1", NewInterval(time.Now().Add(50*time.Millisecond), 100*time.Millisecond), func(ctx context.Context) {
+				if atomic.AddInt32(&counter, 1) == 1 {
+					started <- struct{}{}
+					<-release
+				}
+			})
+			assert.Nil(t, err)
+
+			<-started
+			assert.Nil(t, schedule.Pause("add#1"))
+			assert.Nil(t, schedule.Resume("add#1"))
+			close(release)
+
+			time.Sleep(20 * time.Millisecond)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&counter) == 2
+			}, time.Second, 10*time.Millisecond)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Pause and resume twice or unknown key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+			assert.Equal(t, ErrKeyIsNotPaused, schedule.Resume("add#1"))
+			assert.Nil(t, schedule.Pause("add#1"))
+			assert.Equal(t, ErrKeyIsPaused, schedule.Pause("add#1"))
+			assert.Equal(t, ErrKeyIsNotExists, schedule.Pause("add#2"))
+			assert.Equal(t, ErrKeyIsNotExists, schedule.Resume("add#2"))
+		})
+
+		t.Run("Pause running key", func(t *testing.T) {
+			t.Parallel()
+			started, release := make(chan struct{}), make(chan struct{})
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {
+				close(started)
+				<-release
+			}))
+
+			<-started
+			assert.Equal(t, ErrKeyIsRunning, schedule.Pause("add#1"))
+			assert.Equal(t, 0, schedule.Paused())
+			close(release)
+		})
+	})
+}
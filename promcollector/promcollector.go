@@ -0,0 +1,135 @@
+package promcollector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	scheduler "github.com/sodri126/go-simple-scheduler"
+)
+
+const (
+	defaultNamespace = "scheduler"
+
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeSkipped = "skipped"
+)
+
+// Config keeps the job keys out of the metrics unless LabelValues maps them
+// into Labels, e.g. by using the handler name.
+type Config struct {
+	Namespace   string
+	ConstLabels prometheus.Labels
+	Labels      []string
+	LabelValues func(event scheduler.Event) []string
+	Buckets     []float64
+}
+
+type Collector struct {
+	scheduler.NopListener
+
+	scheduler  *scheduler.Scheduler
+	config     Config
+	pending    *prometheus.Desc
+	running    *prometheus.Desc
+	paused     *prometheus.Desc
+	executions *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	lateness   *prometheus.HistogramVec
+}
+
+func NewCollector(s *scheduler.Scheduler, configs ...Config) *Collector {
+	config := Config{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.Namespace == "" {
+		config.Namespace = defaultNamespace
+	}
+
+	if config.Buckets == nil {
+		config.Buckets = prometheus.DefBuckets
+	}
+
+	newDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(config.Namespace, "", name), help, nil, config.ConstLabels)
+	}
+
+	c := &Collector{
+		scheduler: s,
+		config:    config,
+		pending:   newDesc("jobs_pending", "Number of jobs waiting to be fired."),
+		running:   newDesc("jobs_running", "Number of jobs being executed."),
+		paused:    newDesc("jobs_paused", "Number of paused jobs."),
+		executions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   config.Namespace,
+			Name:        "executions_total",
+			Help:        "Number of executions by outcome.",
+			ConstLabels: config.ConstLabels,
+		}, append([]string{"outcome"}, config.Labels...)),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Name:        "execution_duration_seconds",
+			Help:        "Duration of executions.",
+			ConstLabels: config.ConstLabels,
+			Buckets:     config.Buckets,
+		}, config.Labels),
+		lateness: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Name:        "execution_lateness_seconds",
+			Help:        "Delay between the scheduled date time and the start of executions.",
+			ConstLabels: config.ConstLabels,
+			Buckets:     config.Buckets,
+		}, config.Labels),
+	}
+
+	s.AddListener(c)
+	return c
+}
+
+func (c *Collector) labelValues(event scheduler.Event) []string {
+	if c.config.LabelValues == nil {
+		return make([]string, len(c.config.Labels))
+	}
+
+	return c.config.LabelValues(event)
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pending
+	ch <- c.running
+	ch <- c.paused
+	c.executions.Describe(ch)
+	c.duration.Describe(ch)
+	c.lateness.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(c.scheduler.Pending()))
+	ch <- prometheus.MustNewConstMetric(c.running, prometheus.GaugeValue, float64(c.scheduler.Running()))
+	ch <- prometheus.MustNewConstMetric(c.paused, prometheus.GaugeValue, float64(c.scheduler.Paused()))
+	c.executions.Collect(ch)
+	c.duration.Collect(ch)
+	c.lateness.Collect(ch)
+}
+
+func (c *Collector) OnStart(event scheduler.Event) {
+	c.lateness.WithLabelValues(c.labelValues(event)...).Observe(event.StartTime.Sub(event.DateTime).Seconds())
+}
+
+func (c *Collector) OnSuccess(event scheduler.Event) {
+	c.observe(OutcomeSuccess, event)
+}
+
+func (c *Collector) OnFailure(event scheduler.Event) {
+	c.observe(OutcomeFailure, event)
+}
+
+func (c *Collector) OnSkipped(event scheduler.Event) {
+	c.executions.WithLabelValues(append([]string{OutcomeSkipped}, c.labelValues(event)...)...).Inc()
+}
+
+func (c *Collector) observe(outcome string, event scheduler.Event) {
+	labelValues := c.labelValues(event)
+	c.executions.WithLabelValues(append([]string{outcome}, labelValues...)...).Inc()
+	c.duration.WithLabelValues(labelValues...).Observe(event.EndTime.Sub(event.StartTime).Seconds())
+}
@@ -0,0 +1,100 @@
+package promcollector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+var fn = func(ctx context.Context) {}
+
+func TestCollector(t *testing.T) {
+	t.Run("Gauges and executions without labels", func(t *testing.T) {
+		schedule := scheduler.NewScheduler()
+		collector := NewCollector(schedule)
+		release := make(chan struct{})
+
+		assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+		assert.Nil(t, schedule.Add("add#2", time.Hour, fn))
+		assert.Nil(t, schedule.Pause("add#2"))
+		assert.Nil(t, schedule.Add("add#3", 10*time.Millisecond, func(ctx context.Context) {
+			<-release
+		}))
+		assert.Nil(t, schedule.Add("add#4", 10*time.Millisecond, func(ctx context.Context) {
+			scheduler.Fail(ctx, errors.New("failure"))
+		}))
+		time.Sleep(50 * time.Millisecond)
+
+		expected := `
+# HELP scheduler_jobs_paused Number of paused jobs.
+# TYPE scheduler_jobs_paused gauge
+scheduler_jobs_paused 1
+# HELP scheduler_jobs_pending Number of jobs waiting to be fired.
+# TYPE scheduler_jobs_pending gauge
+scheduler_jobs_pending 2
+# HELP scheduler_jobs_running Number of jobs being executed.
+# TYPE scheduler_jobs_running gauge
+scheduler_jobs_running 1
+`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+			"scheduler_jobs_paused", "scheduler_jobs_pending", "scheduler_jobs_running")
+		assert.Nil(t, err)
+
+		close(release)
+		time.Sleep(20 * time.Millisecond)
+		expected = `
+# HELP scheduler_executions_total Number of executions by outcome.
+# TYPE scheduler_executions_total counter
+scheduler_executions_total{outcome="failure"} 1
+scheduler_executions_total{outcome="success"} 1
+`
+		err = testutil.CollectAndCompare(collector, strings.NewReader(expected), "scheduler_executions_total")
+		assert.Nil(t, err)
+
+		registry := prometheus.NewPedanticRegistry()
+		assert.Nil(t, registry.Register(collector))
+		families, err := registry.Gather()
+		assert.Nil(t, err)
+		for _, family := range families {
+			if family.GetName() != "scheduler_execution_lateness_seconds" {
+				continue
+			}
+
+			histogram := family.GetMetric()[0].GetHistogram()
+			assert.Equal(t, uint64(2), histogram.GetSampleCount())
+			assert.Greater(t, histogram.GetSampleSum(), 0.0)
+		}
+	})
+
+	t.Run("Configured labels", func(t *testing.T) {
+		schedule := scheduler.NewScheduler()
+		collector := NewCollector(schedule, Config{
+			Namespace: "app",
+			Labels:    []string{"handler"},
+			LabelValues: func(event scheduler.Event) []string {
+				return []string{event.Handler}
+			},
+		})
+
+		assert.Nil(t, schedule.RegisterHandler("billing", fn))
+		for _, key := range []string{"invoice#1", "invoice#2"} {
+			assert.Nil(t, schedule.AddHandler(key, 10*time.Millisecond, "billing"))
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		expected := `
+# HELP app_executions_total Number of executions by outcome.
+# TYPE app_executions_total counter
+app_executions_total{handler="billing",outcome="success"} 2
+`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "app_executions_total")
+		assert.Nil(t, err)
+	})
+}
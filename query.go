@@ -0,0 +1,226 @@
+package scheduler
+
+import (
+	"encoding/base64"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryOption filters, sorts and pages the jobs returned by Query. KeyGlob
+// follows path.Match, a job must carry every one of Tags and have one of
+// Statuses. From and To bound the date time inclusively when they are set.
+// Cursor continues after the last job of a previous page, Limit 0 returns
+// every job.
+type QueryOption struct {
+	KeyPrefix string
+	KeyGlob   string
+	Tags      []string
+	Statuses  []JobStatus
+	From      time.Time
+	To        time.Time
+	Sort      QuerySort
+	Cursor    string
+	Limit     int
+}
+
+type QueryResult struct {
+	Jobs []*JobInfo
+
+	// Cursor is empty on the last page.
+	Cursor string
+}
+
+type queryCursor struct {
+	dateTime time.Time
+	key      string
+}
+
+func (opt *QueryOption) match(info *JobInfo) bool {
+	if !strings.HasPrefix(info.Key, opt.KeyPrefix) {
+		return false
+	}
+
+	if opt.KeyGlob != "" {
+		if isMatch, _ := path.Match(opt.KeyGlob, info.Key); !isMatch {
+			return false
+		}
+	}
+
+	if !opt.From.IsZero() && info.DateTime.Before(opt.From) {
+		return false
+	}
+
+	if !opt.To.IsZero() && info.DateTime.After(opt.To) {
+		return false
+	}
+
+	for _, tag := range opt.Tags {
+		if !containsString(info.Tags, tag) {
+			return false
+		}
+	}
+
+	if len(opt.Statuses) == 0 {
+		return true
+	}
+
+	for _, status := range opt.Statuses {
+		if status == info.Status {
+			return true
+		}
+	}
+	return false
+}
+
+func (opt *QueryOption) less(a, b *JobInfo) bool {
+	if opt.Sort == QuerySortDateTime && !a.DateTime.Equal(b.DateTime) {
+		return a.DateTime.Before(b.DateTime)
+	}
+
+	return a.Key < b.Key
+}
+
+func (opt *QueryOption) after(info *JobInfo, cursor *queryCursor) bool {
+	return opt.less(&JobInfo{Key: cursor.key, DateTime: cursor.dateTime}, info)
+}
+
+func encodeQueryCursor(info *JobInfo) string {
+	raw := strconv.FormatInt(info.DateTime.UnixNano(), 10) + ":" + info.Key
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeQueryCursor(cursor string) (qc *queryCursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		err = ErrQueryCursor
+		return
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		err = ErrQueryCursor
+		return
+	}
+
+	unixNano, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		err = ErrQueryCursor
+		return
+	}
+
+	qc = &queryCursor{
+		dateTime: time.Unix(0, unixNano),
+		key:      parts[1],
+	}
+	return
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scheduler) jobInfos() []*JobInfo {
+	return s.jobInfosAfter(nil, 0)
+}
+
+// jobInfosAfter returns at most limit jobs ordered by date time and key,
+// starting after cursor when it is set. limit 0 returns every job.
+func (s *Scheduler) jobInfosAfter(cursor *queryCursor, limit int) (res []*JobInfo) {
+	if cursor == nil {
+		cursor = &queryCursor{}
+	}
+
+	s.mutex.RLock()
+	jobs := s.schedulersIndex.After(cursor.dateTime, cursor.key, limit)
+	res = make([]*JobInfo, len(jobs))
+	for i, ds := range jobs {
+		res[i] = ds.toJobInfo()
+	}
+	s.mutex.RUnlock()
+
+	for _, info := range res {
+		s.withLastRun(info)
+	}
+	return
+}
+
+// Query returns the jobs matching opts, see QueryOption.
+func (s *Scheduler) Query(opts ...QueryOption) (res *QueryResult, err error) {
+	opt := QueryOption{}
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	var cursor *queryCursor
+	if opt.Cursor != "" {
+		if cursor, err = decodeQueryCursor(opt.Cursor); err != nil {
+			return
+		}
+	}
+
+	res = &QueryResult{}
+	for _, info := range s.jobInfos() {
+		if opt.match(info) && (cursor == nil || opt.after(info, cursor)) {
+			res.Jobs = append(res.Jobs, info)
+		}
+	}
+
+	sort.Slice(res.Jobs, func(i, j int) bool {
+		return opt.less(res.Jobs[i], res.Jobs[j])
+	})
+
+	if opt.Limit > 0 && len(res.Jobs) > opt.Limit {
+		res.Jobs = res.Jobs[:opt.Limit]
+		res.Cursor = encodeQueryCursor(res.Jobs[opt.Limit-1])
+	}
+	return
+}
+
+// ListQuery renders the page of jobs returned by Query for opt with the
+// first of lcs, default to NewDefaultResponse.
+func (s *Scheduler) ListQuery(w io.Writer, opt QueryOption, lcs ...ListConverter) (n int, err error) {
+	res, err := s.Query(opt)
+	if err != nil {
+		return
+	}
+
+	lc := NewDefaultResponse()
+	if len(lcs) > 0 {
+		lc = lcs[0]
+	}
+
+	return writeList(w, iterateResponse(toResponseSchedulers(res.Jobs)), lc)
+}
+
+func (s *Scheduler) iterateJobs() ResponseIterator {
+	var (
+		cursor *queryCursor
+		batch  []*ResponseScheduler
+	)
+
+	return func() (res *ResponseScheduler, ok bool) {
+		if len(batch) == 0 {
+			batch = toResponseSchedulers(s.jobInfosAfter(cursor, listBatchSize))
+			if len(batch) > 0 {
+				last := batch[len(batch)-1]
+				cursor = &queryCursor{dateTime: last.Time, key: last.Key}
+			}
+		}
+
+		if len(batch) == 0 {
+			return
+		}
+
+		res, batch = batch[0], batch[1:]
+		return res, true
+	}
+}
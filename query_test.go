@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func queryKeys(jobs []*JobInfo) (keys []string) {
+	for _, job := range jobs {
+		keys = append(keys, job.Key)
+	}
+	return
+}
+
+func TestQueryScheduler(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Filter jobs", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			now := time.Now()
+			assert.Nil(t, schedule.AddDate("billing:1", now.Add(3*time.Hour), fn, JobOption{Tags: []string{"billing", "daily"}}))
+			assert.Nil(t, schedule.AddDate("billing:2", now.Add(time.Hour), fn, JobOption{Tags: []string{"billing"}}))
+			assert.Nil(t, schedule.AddDate("report:1", now.Add(2*time.Hour), fn, JobOption{Tags: []string{"daily"}}))
+			assert.Nil(t, schedule.Pause("report:1"))
+
+			res, err := schedule.Query()
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"billing:2", "report:1", "billing:1"}, queryKeys(res.Jobs))
+			assert.Empty(t, res.Cursor)
+
+			res, err = schedule.Query(QueryOption{KeyPrefix: "billing:", Sort: QuerySortKey})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"billing:1", "billing:2"}, queryKeys(res.Jobs))
+
+			res, err = schedule.Query(QueryOption{KeyGlob: "*:1", Sort: QuerySortKey})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"billing:1", "report:1"}, queryKeys(res.Jobs))
+
+			res, err = schedule.Query(QueryOption{Tags: []string{"billing", "daily"}})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"billing:1"}, queryKeys(res.Jobs))
+
+			res, err = schedule.Query(QueryOption{Statuses: []JobStatus{JobStatusPaused}})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"report:1"}, queryKeys(res.Jobs))
+
+			res, err = schedule.Query(QueryOption{From: now.Add(90 * time.Minute), To: now.Add(3 * time.Hour)})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"report:1", "billing:1"}, queryKeys(res.Jobs))
+		})
+
+		t.Run("Paginate jobs", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			dateTime := time.Now().Add(time.Hour)
+			for i := 0; i < 25; i++ {
+				assert.Nil(t, schedule.AddDate(fmt.Sprintf("add#%02d", i), dateTime, fn))
+			}
+
+			var keys []string
+			opt := QueryOption{Limit: 10}
+			for pages := 1; ; pages++ {
+				res, err := schedule.Query(opt)
+				assert.Nil(t, err)
+				keys = append(keys, queryKeys(res.Jobs)...)
+				if res.Cursor == "" {
+					assert.Equal(t, 3, pages)
+					break
+				}
+
+				opt.Cursor = res.Cursor
+			}
+
+			assert.Len(t, keys, 25)
+			assert.Equal(t, "add#00", keys[0])
+			assert.Equal(t, "add#24", keys[24])
+		})
+
+		t.Run("List queried jobs", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("billing:1", time.Hour, fn))
+			assert.Nil(t, schedule.Add("report:1", time.Hour, fn))
+
+			buf := &bytes.Buffer{}
+			_, err := schedule.ListQuery(buf, QueryOption{KeyPrefix: "billing:"}, NewJsonResponse())
+			assert.Nil(t, err)
+			assert.Contains(t, buf.String(), "billing:1")
+			assert.NotContains(t, buf.String(), "report:1")
+		})
+
+		t.Run("List after cancel keeps date time order", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("add#1", time.Hour, fn))
+			assert.Nil(t, schedule.Add("add#2", time.Hour, fn))
+			assert.Nil(t, schedule.Add("add#3", time.Hour, fn))
+			assert.Nil(t, schedule.Cancel("add#1"))
+			assert.Nil(t, schedule.Cancel("add#3"))
+			assert.Nil(t, schedule.Add("add#4", time.Hour, fn))
+
+			var keys []string
+			for _, res := range schedule.toResponseScheduler() {
+				keys = append(keys, res.Key)
+			}
+			assert.Equal(t, []string{"add#2", "add#4"}, keys)
+		})
+
+		t.Run("Iterate while cancelling", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			total := 2*listBatchSize + 10
+			for i := 0; i < total; i++ {
+				assert.Nil(t, schedule.Add(fmt.Sprintf("add#%05d", i), time.Hour, fn))
+			}
+
+			next := schedule.iterateJobs()
+			seen := make(map[string]int)
+			for i := 0; i < listBatchSize; i++ {
+				res, ok := next()
+				assert.True(t, ok)
+				seen[res.Key]++
+			}
+
+			for i := 0; i < 10; i++ {
+				assert.Nil(t, schedule.Cancel(fmt.Sprintf("add#%05d", i)))
+			}
+
+			for res, ok := next(); ok; res, ok = next() {
+				seen[res.Key]++
+			}
+			assert.Len(t, seen, total)
+			for key, count := range seen {
+				assert.Equal(t, 1, count, key)
+			}
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Invalid cursor", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			_, err := schedule.Query(QueryOption{Cursor: "!"})
+			assert.Equal(t, ErrQueryCursor, err)
+		})
+	})
+}
@@ -0,0 +1,307 @@
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	scheduler "github.com/sodri126/go-simple-scheduler"
+)
+
+const (
+	defaultPrefix = "{scheduler}"
+)
+
+var (
+	addScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[2], ARGV[1]) == 1 then
+	return 0
+end
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[3])
+redis.call('HSET', KEYS[3], ARGV[1], ARGV[2])
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+return 1
+`)
+
+	rescheduleScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[2], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[3], ARGV[1], ARGV[2])
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('ZREM', KEYS[4], ARGV[1])
+redis.call('HDEL', KEYS[5], ARGV[1])
+return 1
+`)
+
+	cancelScript = redis.NewScript(`
+if redis.call('HDEL', KEYS[2], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HDEL', KEYS[3], ARGV[1])
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('ZREM', KEYS[4], ARGV[1])
+redis.call('HDEL', KEYS[5], ARGV[1])
+redis.call('HDEL', KEYS[6], ARGV[1])
+return 1
+`)
+
+	lockScript = redis.NewScript(`
+local owner = redis.call('GET', KEYS[1])
+if owner and owner ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+	unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+end
+return 1
+`)
+
+	claimScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local res = {}
+for _, key in ipairs(due) do
+	table.insert(res, key)
+	table.insert(res, redis.call('HGET', KEYS[3], key))
+	table.insert(res, redis.call('HGET', KEYS[2], key))
+	table.insert(res, tostring(redis.call('HINCRBY', KEYS[6], key, 1)))
+	redis.call('ZREM', KEYS[1], key)
+	redis.call('HDEL', KEYS[2], key)
+	redis.call('HDEL', KEYS[3], key)
+	redis.call('HDEL', KEYS[6], key)
+end
+return res
+`)
+
+	leaseScript = redis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[4], '-inf', ARGV[1])
+for _, key in ipairs(expired) do
+	redis.call('ZREM', KEYS[4], key)
+	redis.call('HDEL', KEYS[5], key)
+	redis.call('ZADD', KEYS[1], ARGV[1], key)
+end
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local res = {}
+for _, key in ipairs(due) do
+	table.insert(res, key)
+	table.insert(res, redis.call('HGET', KEYS[3], key))
+	table.insert(res, redis.call('HGET', KEYS[2], key))
+	table.insert(res, tostring(redis.call('HINCRBY', KEYS[6], key, 1)))
+	redis.call('ZREM', KEYS[1], key)
+	redis.call('ZADD', KEYS[4], ARGV[3], key)
+	redis.call('HSET', KEYS[5], key, ARGV[4])
+end
+return res
+`)
+
+	ackScript = redis.NewScript(`
+if redis.call('HGET', KEYS[5], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('ZREM', KEYS[4], ARGV[1])
+redis.call('HDEL', KEYS[5], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+redis.call('HDEL', KEYS[6], ARGV[1])
+return 1
+`)
+
+	releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[5], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('ZREM', KEYS[4], ARGV[1])
+redis.call('HDEL', KEYS[5], ARGV[1])
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+return 1
+`)
+)
+
+type Config struct {
+	Prefix string
+}
+
+type Store struct {
+	client redis.UniversalClient
+	prefix string
+	keys   []string
+}
+
+type storeData struct {
+	Handler string `json:"handler"`
+}
+
+func NewStore(client redis.UniversalClient, configs ...Config) *Store {
+	config := Config{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.Prefix == "" {
+		config.Prefix = defaultPrefix
+	}
+
+	return &Store{
+		client: client,
+		prefix: config.Prefix,
+		keys: []string{
+			config.Prefix + ":queue",
+			config.Prefix + ":jobs",
+			config.Prefix + ":times",
+			config.Prefix + ":processing",
+			config.Prefix + ":owners",
+			config.Prefix + ":attempts",
+		},
+	}
+}
+
+func (s *Store) Add(ctx context.Context, job *scheduler.StoreJob) (err error) {
+	data, err := json.Marshal(&storeData{Handler: job.Handler})
+	if err != nil {
+		return
+	}
+
+	ok, err := addScript.Run(ctx, s.client, s.keys, job.Key, toScore(job.DateTime), data).Int()
+	if err != nil {
+		return
+	}
+
+	if ok == 0 {
+		err = scheduler.ErrKeyIsExists
+	}
+	return
+}
+
+func (s *Store) Reschedule(ctx context.Context, key string, dateTime time.Time) (err error) {
+	ok, err := rescheduleScript.Run(ctx, s.client, s.keys, key, toScore(dateTime)).Int()
+	if err != nil {
+		return
+	}
+
+	if ok == 0 {
+		err = scheduler.ErrKeyIsNotExists
+	}
+	return
+}
+
+func (s *Store) Cancel(ctx context.Context, key string) (err error) {
+	ok, err := cancelScript.Run(ctx, s.client, s.keys, key).Int()
+	if err != nil {
+		return
+	}
+
+	if ok == 0 {
+		err = scheduler.ErrKeyIsNotExists
+	}
+	return
+}
+
+func (s *Store) Claim(ctx context.Context, now time.Time, limit int) (jobs []*scheduler.StoreJob, err error) {
+	res, err := claimScript.Run(ctx, s.client, s.keys, toScore(now), limit).StringSlice()
+	if err != nil {
+		return
+	}
+
+	return toStoreJobs(res)
+}
+
+func (s *Store) Lease(ctx context.Context, owner string, now time.Time, ttl time.Duration, limit int) (jobs []*scheduler.StoreJob, err error) {
+	res, err := leaseScript.Run(ctx, s.client, s.keys, toScore(now), limit, toScore(now.Add(ttl)), owner).StringSlice()
+	if err != nil {
+		return
+	}
+
+	return toStoreJobs(res)
+}
+
+func (s *Store) Ack(ctx context.Context, key, owner string) (err error) {
+	ok, err := ackScript.Run(ctx, s.client, s.keys, key, owner).Int()
+	if err != nil {
+		return
+	}
+
+	if ok == 0 {
+		err = scheduler.ErrLeaseIsNotHeld
+	}
+	return
+}
+
+func (s *Store) Release(ctx context.Context, key, owner string, dateTime time.Time) (err error) {
+	ok, err := releaseScript.Run(ctx, s.client, s.keys, key, owner, toScore(dateTime)).Int()
+	if err != nil {
+		return
+	}
+
+	if ok == 0 {
+		err = scheduler.ErrLeaseIsNotHeld
+	}
+	return
+}
+
+func (s *Store) Lock(ctx context.Context, name, owner string, ttl time.Duration) (isLocked bool, err error) {
+	ok, err := lockScript.Run(ctx, s.client, []string{s.lockKey(name)}, owner, ttl.Milliseconds()).Int()
+	isLocked = err == nil && ok == 1
+	return
+}
+
+func (s *Store) Unlock(ctx context.Context, name, owner string) (err error) {
+	err = unlockScript.Run(ctx, s.client, []string{s.lockKey(name)}, owner).Err()
+	return
+}
+
+func (s *Store) lockKey(name string) string {
+	return s.prefix + ":lock:" + name
+}
+
+func toScore(dateTime time.Time) int64 {
+	return dateTime.UnixNano() / int64(time.Millisecond)
+}
+
+func toStoreJobs(res []string) (jobs []*scheduler.StoreJob, err error) {
+	for i := 0; i+3 < len(res); i += 4 {
+		var job *scheduler.StoreJob
+		job, err = toStoreJob(res[i], res[i+1], res[i+2], res[i+3])
+		if err != nil {
+			return
+		}
+
+		jobs = append(jobs, job)
+	}
+	return
+}
+
+func toStoreJob(key, score, data, attempt string) (job *scheduler.StoreJob, err error) {
+	ms, err := strconv.ParseInt(score, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("redisstore: invalid date time of %q: %w", key, err)
+		return
+	}
+
+	attempts, err := strconv.Atoi(attempt)
+	if err != nil {
+		err = fmt.Errorf("redisstore: invalid attempt of %q: %w", key, err)
+		return
+	}
+
+	var sd storeData
+	if err = json.Unmarshal([]byte(data), &sd); err != nil {
+		err = fmt.Errorf("redisstore: invalid data of %q: %w", key, err)
+		return
+	}
+
+	job = &scheduler.StoreJob{
+		Key:      key,
+		Handler:  sd.Handler,
+		DateTime: time.Unix(0, ms*int64(time.Millisecond)),
+		Attempt:  attempts,
+	}
+	return
+}
@@ -0,0 +1,341 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStore(t *testing.T) *Store {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return NewStore(client)
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Claim due jobs in order of date time", func(t *testing.T) {
+			store := newStore(t)
+			now := time.Now()
+			for i := 1; i <= 5; i++ {
+				err := store.Add(ctx, &scheduler.StoreJob{
+					Key:      fmt.Sprintf("add#%d", i),
+					Handler:  "handler",
+					DateTime: now.Add(time.Duration(5-i) * time.Second),
+				})
+				assert.Nil(t, err)
+			}
+
+			jobs, err := store.Claim(ctx, now.Add(2*time.Second), 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 3)
+			assert.Equal(t, "add#5", jobs[0].Key)
+			assert.Equal(t, "add#4", jobs[1].Key)
+			assert.Equal(t, "add#3", jobs[2].Key)
+			assert.Equal(t, "handler", jobs[0].Handler)
+			assert.WithinDuration(t, now, jobs[0].DateTime, time.Millisecond)
+
+			jobs, err = store.Claim(ctx, now.Add(2*time.Second), 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 0)
+		})
+
+		t.Run("Claim respects the limit", func(t *testing.T) {
+			store := newStore(t)
+			now := time.Now()
+			for i := 1; i <= 5; i++ {
+				err := store.Add(ctx, &scheduler.StoreJob{Key: fmt.Sprintf("add#%d", i), DateTime: now})
+				assert.Nil(t, err)
+			}
+
+			jobs, err := store.Claim(ctx, now, 2)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 2)
+			jobs, err = store.Claim(ctx, now, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 3)
+		})
+
+		t.Run("Reschedule and cancel", func(t *testing.T) {
+			store := newStore(t)
+			now := time.Now()
+			assert.Nil(t, store.Add(ctx, &scheduler.StoreJob{Key: "add#1", DateTime: now}))
+			assert.Nil(t, store.Add(ctx, &scheduler.StoreJob{Key: "add#2", DateTime: now}))
+			assert.Nil(t, store.Reschedule(ctx, "add#1", now.Add(time.Hour)))
+			assert.Nil(t, store.Cancel(ctx, "add#2"))
+
+			jobs, err := store.Claim(ctx, now, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 0)
+
+			jobs, err = store.Claim(ctx, now.Add(time.Hour), 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			assert.Equal(t, "add#1", jobs[0].Key)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Key is exists", func(t *testing.T) {
+			store := newStore(t)
+			assert.Nil(t, store.Add(ctx, &scheduler.StoreJob{Key: "add#1", DateTime: time.Now()}))
+			err := store.Add(ctx, &scheduler.StoreJob{Key: "add#1", DateTime: time.Now()})
+			assert.Equal(t, scheduler.ErrKeyIsExists, err)
+		})
+
+		t.Run("Key is not exists", func(t *testing.T) {
+			store := newStore(t)
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, store.Reschedule(ctx, "add#1", time.Now()))
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, store.Cancel(ctx, "add#1"))
+		})
+	})
+}
+
+func TestSchedulerWithStore(t *testing.T) {
+	t.Run("Job added on one replica runs once on any replica", func(t *testing.T) {
+		var (
+			store   = newStore(t)
+			counter int32
+			wg      sync.WaitGroup
+		)
+
+		wg.Add(100)
+		replicas := make([]*scheduler.Scheduler, 3)
+		for i := range replicas {
+			replicas[i] = scheduler.NewScheduler(scheduler.Config{
+				Store:        store,
+				PollInterval: 10 * time.Millisecond,
+			})
+			defer replicas[i].Stop()
+			err := replicas[i].RegisterHandler("count", func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+				wg.Done()
+			})
+			assert.Nil(t, err)
+		}
+
+		for i := 1; i <= 100; i++ {
+			err := replicas[i%len(replicas)].AddHandler(fmt.Sprintf("add#%d", i), 50*time.Millisecond, "count")
+			assert.Nil(t, err)
+		}
+
+		wg.Wait()
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, int32(100), atomic.LoadInt32(&counter))
+	})
+
+	t.Run("Cancel and reschedule from another replica", func(t *testing.T) {
+		var (
+			store   = newStore(t)
+			counter int32
+		)
+
+		first := scheduler.NewScheduler(scheduler.Config{Store: store, PollInterval: 10 * time.Millisecond})
+		second := scheduler.NewScheduler(scheduler.Config{Store: store, PollInterval: 10 * time.Millisecond})
+		defer first.Stop()
+		defer second.Stop()
+		for _, s := range []*scheduler.Scheduler{first, second} {
+			assert.Nil(t, s.RegisterHandler("count", func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			}))
+		}
+
+		assert.Nil(t, first.AddHandler("add#1", 100*time.Millisecond, "count"))
+		assert.Nil(t, first.AddHandler("add#2", 100*time.Millisecond, "count"))
+		assert.Nil(t, second.Cancel("add#1"))
+		assert.Nil(t, second.Reschedule("add#2", time.Hour))
+		assert.Equal(t, scheduler.ErrKeyIsNotExists, second.Cancel("add#3"))
+		assert.Equal(t, scheduler.ErrKeyIsExists, second.AddHandler("add#2", time.Second, "count"))
+		assert.Equal(t, scheduler.ErrHandlerIsNotExists, second.AddHandler("add#4", time.Second, "unknown"))
+
+		time.Sleep(250 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&counter))
+	})
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Lock, extend and unlock", func(t *testing.T) {
+			store := newStore(t)
+			isLocked, err := store.Lock(ctx, "lock", "first", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			isLocked, err = store.Lock(ctx, "lock", "first", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			assert.Nil(t, store.Unlock(ctx, "lock", "first"))
+			isLocked, err = store.Lock(ctx, "lock", "second", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+		})
+
+		t.Run("Lock after ttl elapsed", func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			store := NewStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+			isLocked, err := store.Lock(ctx, "lock", "first", time.Second)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			mr.FastForward(2 * time.Second)
+			isLocked, err = store.Lock(ctx, "lock", "second", time.Second)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Lock is held by another owner", func(t *testing.T) {
+			store := newStore(t)
+			isLocked, err := store.Lock(ctx, "lock", "first", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			isLocked, err = store.Lock(ctx, "lock", "second", time.Minute)
+			assert.Nil(t, err)
+			assert.False(t, isLocked)
+
+			assert.Nil(t, store.Unlock(ctx, "lock", "second"))
+			isLocked, err = store.Lock(ctx, "lock", "second", time.Minute)
+			assert.Nil(t, err)
+			assert.False(t, isLocked)
+		})
+	})
+}
+
+func TestSchedulerWithExecutionLocker(t *testing.T) {
+	t.Run("Occurrence runs once across replicas", func(t *testing.T) {
+		var (
+			store    = newStore(t)
+			counter  int32
+			dateTime = time.Now().Add(100 * time.Millisecond)
+		)
+
+		for i := 0; i < 3; i++ {
+			schedule := scheduler.NewScheduler(scheduler.Config{ExecutionLocker: store})
+			for j := 1; j <= 10; j++ {
+				err := schedule.AddDate(fmt.Sprintf("add#%d", j), dateTime, func(ctx context.Context) {
+					atomic.AddInt32(&counter, 1)
+				})
+				assert.Nil(t, err)
+			}
+		}
+
+		time.Sleep(300 * time.Millisecond)
+		assert.Equal(t, int32(10), atomic.LoadInt32(&counter))
+	})
+}
+
+func TestLease(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Lease, release and ack", func(t *testing.T) {
+			store := newStore(t)
+			now := time.Now()
+			assert.Nil(t, store.Add(ctx, &scheduler.StoreJob{Key: "add#1", Handler: "handler", DateTime: now}))
+
+			jobs, err := store.Lease(ctx, "first", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			assert.Equal(t, "handler", jobs[0].Handler)
+			jobs, err = store.Lease(ctx, "second", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 0)
+
+			assert.Equal(t, scheduler.ErrKeyIsExists, store.Add(ctx, &scheduler.StoreJob{Key: "add#1", DateTime: now}))
+			assert.Nil(t, store.Release(ctx, "add#1", "first", now))
+			jobs, err = store.Lease(ctx, "second", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			assert.WithinDuration(t, now, jobs[0].DateTime, time.Millisecond)
+			assert.Equal(t, 2, jobs[0].Attempt)
+
+			assert.Nil(t, store.Ack(ctx, "add#1", "second"))
+			assert.Equal(t, scheduler.ErrKeyIsNotExists, store.Cancel(ctx, "add#1"))
+		})
+
+		t.Run("Reclaim expired lease", func(t *testing.T) {
+			store := newStore(t)
+			now := time.Now()
+			assert.Nil(t, store.Add(ctx, &scheduler.StoreJob{Key: "add#1", DateTime: now}))
+
+			jobs, err := store.Lease(ctx, "first", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			jobs, err = store.Lease(ctx, "second", now.Add(2*time.Minute), time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			assert.WithinDuration(t, now, jobs[0].DateTime, time.Millisecond)
+			assert.Equal(t, scheduler.ErrLeaseIsNotHeld, store.Ack(ctx, "add#1", "first"))
+			assert.Nil(t, store.Ack(ctx, "add#1", "second"))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Ack and release without lease", func(t *testing.T) {
+			store := newStore(t)
+			assert.Nil(t, store.Add(ctx, &scheduler.StoreJob{Key: "add#1", DateTime: time.Now()}))
+			assert.Equal(t, scheduler.ErrLeaseIsNotHeld, store.Ack(ctx, "add#1", "first"))
+			assert.Equal(t, scheduler.ErrLeaseIsNotHeld, store.Release(ctx, "add#1", "first", time.Now()))
+		})
+	})
+}
+
+func TestWorkers(t *testing.T) {
+	var (
+		store  = newStore(t)
+		wg     sync.WaitGroup
+		mutex  sync.Mutex
+		owners = make(map[int]int)
+	)
+
+	wg.Add(200)
+	workers := make([]*scheduler.Scheduler, 4)
+	for i := range workers {
+		index := i
+		workers[i] = scheduler.NewScheduler(scheduler.Config{
+			Store:        store,
+			PollInterval: 5 * time.Millisecond,
+			ClaimLimit:   5,
+			LeaseTTL:     time.Second,
+		})
+		defer workers[i].Stop()
+		err := workers[i].RegisterHandler("handler", func(ctx context.Context) {
+			mutex.Lock()
+			owners[index]++
+			mutex.Unlock()
+			time.Sleep(time.Millisecond)
+			wg.Done()
+		})
+		assert.Nil(t, err)
+	}
+
+	for i := 1; i <= 200; i++ {
+		assert.Nil(t, workers[i%len(workers)].AddHandler(fmt.Sprintf("add#%d", i), 0, "handler"))
+	}
+
+	wg.Wait()
+	assert.Len(t, owners, len(workers))
+	time.Sleep(50 * time.Millisecond)
+	jobs, err := store.Claim(context.Background(), time.Now().Add(time.Hour), 1000)
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 0)
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy runs a failed job again until it succeeds or has run
+// MaxAttempts times, the attempt is reported in Event.Attempt. The delay
+// before the second attempt is Delay, it is multiplied by Multiplier for
+// every next attempt and bounded by MaxDelay. IsRetryable, when set, stops
+// the retries on the errors it rejects.
+//
+// It applies to the jobs claimed from Config.Store too, leased jobs are
+// retried by Config.LeaseRetryDelay instead.
+type RetryPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	IsRetryable func(err error) bool
+}
+
+func (p *RetryPolicy) allows(attempt int, err error) bool {
+	return attempt < p.MaxAttempts && (p.IsRetryable == nil || p.IsRetryable(err))
+}
+
+// delay returns the delay before the attempt following attempt.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.Delay
+	for i := 1; i < attempt && p.Multiplier > 1; i++ {
+		delay = time.Duration(float64(delay) * p.Multiplier)
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// executeRetrying executes fn and runs it again on failure as allowed by
+// the retry policy, until the job is cancelled or the scheduler stopped.
+func (s *Scheduler) executeRetrying(event Event, fn FnScheduler) {
+	policy := &s.config.RetryPolicy
+	for {
+		isExecuted, err := s.execute(event, fn)
+		if !isExecuted || err == nil || !policy.allows(event.Attempt, err) || !s.wait(event.Key, policy.delay(event.Attempt)) {
+			return
+		}
+
+		event.Attempt++
+	}
+}
+
+// wait sleeps for delay, it returns false when the job of key is cancelled
+// or the scheduler stopped meanwhile.
+func (s *Scheduler) wait(key string, delay time.Duration) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mutex.Lock()
+	id := s.track(key, cancel)
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		s.untrack(key, id)
+		s.mutex.Unlock()
+	}()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-s.done:
+		return false
+	}
+}
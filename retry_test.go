@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Retry until success", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			schedule := NewScheduler(Config{RetryPolicy: RetryPolicy{MaxAttempts: 5, Delay: 10 * time.Millisecond}})
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {
+				if atomic.AddInt32(&counter, 1) < 3 {
+					Fail(ctx, errors.New("failure"))
+				}
+			}))
+
+			assert.Eventually(t, func() bool {
+				return len(schedule.History("add#1")) == 3
+			}, time.Second, 5*time.Millisecond)
+			history := schedule.History("add#1")
+			for i, outcome := range []Outcome{OutcomeFailure, OutcomeFailure, OutcomeSuccess} {
+				assert.Equal(t, i+1, history[i].Attempt)
+				assert.Equal(t, outcome, history[i].Outcome)
+				assert.Equal(t, history[0].DateTime, history[i].DateTime)
+			}
+		})
+
+		t.Run("Retry claimed job", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			schedule := NewScheduler(Config{
+				Store:        NewMemoryStore(),
+				PollInterval: 5 * time.Millisecond,
+				RetryPolicy:  RetryPolicy{MaxAttempts: 5, Delay: 10 * time.Millisecond},
+			})
+			t.Cleanup(schedule.Stop)
+			assert.Nil(t, schedule.RegisterHandler("handler", func(ctx context.Context) {
+				if atomic.AddInt32(&counter, 1) < 3 {
+					Fail(ctx, errors.New("failure"))
+				}
+			}))
+			assert.Nil(t, schedule.AddHandler("add#1", 0, "handler"))
+
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&counter) == 3
+			}, time.Second, 5*time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, int32(3), atomic.LoadInt32(&counter))
+		})
+
+		t.Run("Delay", func(t *testing.T) {
+			t.Parallel()
+			policy := &RetryPolicy{Delay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second}
+			var delays []time.Duration
+			for attempt := 1; attempt <= 5; attempt++ {
+				delays = append(delays, policy.delay(attempt))
+			}
+			assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+			assert.Equal(t, time.Second, (&RetryPolicy{Delay: time.Second}).delay(3))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Attempts exhausted or not retryable", func(t *testing.T) {
+			t.Parallel()
+			permanent := errors.New("permanent")
+			schedule := NewScheduler(Config{RetryPolicy: RetryPolicy{
+				MaxAttempts: 2,
+				Delay:       time.Millisecond,
+				IsRetryable: func(err error) bool { return !errors.Is(err, permanent) },
+			}})
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {
+				Fail(ctx, errors.New("failure"))
+			}))
+			assert.Nil(t, schedule.Add("add#2", 10*time.Millisecond, func(ctx context.Context) {
+				Fail(ctx, permanent)
+			}))
+
+			time.Sleep(100 * time.Millisecond)
+			assert.Len(t, schedule.History("add#1"), 2)
+			assert.Len(t, schedule.History("add#2"), 1)
+		})
+
+		t.Run("Cancel stops the retries", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler(Config{RetryPolicy: RetryPolicy{MaxAttempts: 2, Delay: time.Hour}})
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, func(ctx context.Context) {
+				Fail(ctx, errors.New("failure"))
+			}))
+			assert.Eventually(t, func() bool {
+				return len(schedule.History("add#1")) == 1
+			}, time.Second, 5*time.Millisecond)
+
+			assert.Nil(t, schedule.Cancel("add#1"))
+			assert.Eventually(t, func() bool {
+				schedule.mutex.RLock()
+				defer schedule.mutex.RUnlock()
+				return len(schedule.executions) == 0
+			}, time.Second, 5*time.Millisecond)
+			assert.Len(t, schedule.History("add#1"), 1)
+		})
+	})
+}
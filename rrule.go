@@ -0,0 +1,677 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	rruleDateFormat = "20060102"
+
+	// rruleMaxEmptyPeriods and rruleMaxYears bound the search of an
+	// occurrence so a rule which never matches, like the 30th of February,
+	// ends.
+	rruleMaxEmptyPeriods = 1 << 16
+	rruleMaxYears        = 400
+)
+
+type Frequency int
+
+const (
+	FrequencySecondly Frequency = iota + 1
+	FrequencyMinutely
+	FrequencyHourly
+	FrequencyDaily
+	FrequencyWeekly
+	FrequencyMonthly
+	FrequencyYearly
+)
+
+var (
+	frequencyNames = map[Frequency]string{
+		FrequencySecondly: "SECONDLY",
+		FrequencyMinutely: "MINUTELY",
+		FrequencyHourly:   "HOURLY",
+		FrequencyDaily:    "DAILY",
+		FrequencyWeekly:   "WEEKLY",
+		FrequencyMonthly:  "MONTHLY",
+		FrequencyYearly:   "YEARLY",
+	}
+
+	weekdayNames = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+)
+
+type rruleWeekday struct {
+	weekday time.Weekday
+	n       int
+}
+
+// RRule is a recurrence rule of RFC 5545 starting at the date time of
+// Start, in its time zone. BYSETPOS, BYYEARDAY and BYWEEKNO are not
+// supported. The date times excluded by Exclude are skipped and the date
+// times added by Include occur besides the rule.
+type RRule struct {
+	start      time.Time
+	freq       Frequency
+	interval   int
+	count      int
+	until      time.Time
+	byMonth    []int
+	byMonthDay []int
+	byDay      []rruleWeekday
+	byHour     []int
+	byMinute   []int
+	bySecond   []int
+	weekStart  time.Weekday
+	exDates    []time.Time
+	rDates     []time.Time
+}
+
+// ParseRRule parses the value of a RRULE property, like
+// "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10". An empty rule only occurs at start.
+func ParseRRule(start time.Time, rule string) (r *RRule, err error) {
+	r = &RRule{
+		start:     start.Truncate(time.Second),
+		interval:  1,
+		weekStart: time.Monday,
+	}
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return
+	}
+
+	for _, part := range strings.Split(rule, ";") {
+		name, value, isFound := strings.Cut(part, "=")
+		if !isFound {
+			return nil, fmt.Errorf("%w: %q", ErrRRuleFormat, part)
+		}
+
+		if err = r.set(strings.ToUpper(name), value); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrRRuleFormat, name, err)
+		}
+	}
+
+	if r.freq == 0 {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrRRuleFormat)
+	}
+	return
+}
+
+func (r *RRule) set(name, value string) (err error) {
+	switch name {
+	case "FREQ":
+		for freq, freqName := range frequencyNames {
+			if freqName == value {
+				r.freq = freq
+				return
+			}
+		}
+		err = fmt.Errorf("unknown frequency %q", value)
+	case "INTERVAL":
+		r.interval, err = strconv.Atoi(value)
+		if err == nil && r.interval <= 0 {
+			err = fmt.Errorf("interval %d is not positive", r.interval)
+		}
+	case "COUNT":
+		r.count, err = strconv.Atoi(value)
+		if err == nil && r.count <= 0 {
+			err = fmt.Errorf("count %d is not positive", r.count)
+		}
+	case "UNTIL":
+		r.until, err = parseRRuleUntil(value, r.start.Location())
+	case "BYMONTH":
+		r.byMonth, err = parseRRuleInts(value, 1, 12, false)
+	case "BYMONTHDAY":
+		r.byMonthDay, err = parseRRuleInts(value, 1, 31, true)
+	case "BYHOUR":
+		r.byHour, err = parseRRuleInts(value, 0, 23, false)
+	case "BYMINUTE":
+		r.byMinute, err = parseRRuleInts(value, 0, 59, false)
+	case "BYSECOND":
+		r.bySecond, err = parseRRuleInts(value, 0, 59, false)
+	case "BYDAY":
+		r.byDay, err = parseRRuleWeekdays(value)
+	case "WKST":
+		r.weekStart, err = parseRRuleWeekday(value)
+	default:
+		err = fmt.Errorf("%s is not supported", name)
+	}
+	return
+}
+
+func parseRRuleUntil(value string, location *time.Location) (until time.Time, err error) {
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(icalDateTimeFormat+"Z", value)
+	case len(value) == len(rruleDateFormat):
+		until, err = time.ParseInLocation(rruleDateFormat, value, location)
+		return until.AddDate(0, 0, 1).Add(-time.Second), err
+	default:
+		return time.ParseInLocation(icalDateTimeFormat, value, location)
+	}
+}
+
+func parseRRuleInts(value string, min, max int, isNegative bool) (res []int, err error) {
+	for _, v := range strings.Split(value, ",") {
+		var n int
+		if n, err = strconv.Atoi(v); err != nil {
+			return
+		}
+
+		abs := n
+		if isNegative && n < 0 {
+			abs = -n
+		}
+
+		if abs < min || abs > max {
+			err = fmt.Errorf("%d is out of range", n)
+			return
+		}
+
+		res = append(res, n)
+	}
+	return
+}
+
+func parseRRuleWeekday(value string) (time.Weekday, error) {
+	for i, name := range weekdayNames {
+		if name == value {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", value)
+}
+
+func parseRRuleWeekdays(value string) (res []rruleWeekday, err error) {
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			err = fmt.Errorf("unknown weekday %q", v)
+			return
+		}
+
+		wd := rruleWeekday{}
+		if prefix := v[:len(v)-2]; prefix != "" {
+			if wd.n, err = strconv.Atoi(prefix); err != nil {
+				return
+			}
+
+			if wd.n == 0 || wd.n > 53 || wd.n < -53 {
+				err = fmt.Errorf("%d is out of range", wd.n)
+				return
+			}
+		}
+
+		if wd.weekday, err = parseRRuleWeekday(v[len(v)-2:]); err != nil {
+			return
+		}
+
+		res = append(res, wd)
+	}
+	return
+}
+
+// Exclude skips the occurrences at dateTimes, as EXDATE.
+func (r *RRule) Exclude(dateTimes ...time.Time) {
+	r.exDates = append(r.exDates, dateTimes...)
+}
+
+// Include adds occurrences at dateTimes, as RDATE.
+func (r *RRule) Include(dateTimes ...time.Time) {
+	r.rDates = append(r.rDates, dateTimes...)
+	sort.Slice(r.rDates, func(i, j int) bool {
+		return r.rDates[i].Before(r.rDates[j])
+	})
+}
+
+func (r *RRule) Start() time.Time {
+	return r.start
+}
+
+func (r *RRule) ExDates() []time.Time {
+	return append([]time.Time(nil), r.exDates...)
+}
+
+func (r *RRule) RDates() []time.Time {
+	return append([]time.Time(nil), r.rDates...)
+}
+
+// Rule returns the value of the RRULE property, empty when the rule only
+// occurs at start.
+func (r *RRule) Rule() string {
+	if r.freq == 0 {
+		return ""
+	}
+
+	parts := []string{"FREQ=" + frequencyNames[r.freq]}
+	if r.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.interval))
+	}
+
+	if r.count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.count))
+	}
+
+	if !r.until.IsZero() {
+		parts = append(parts, "UNTIL="+r.until.UTC().Format(icalDateTimeFormat)+"Z")
+	}
+
+	for _, by := range []struct {
+		name   string
+		values []int
+	}{
+		{"BYMONTH", r.byMonth},
+		{"BYMONTHDAY", r.byMonthDay},
+	} {
+		if len(by.values) > 0 {
+			parts = append(parts, by.name+"="+joinInts(by.values))
+		}
+	}
+
+	if len(r.byDay) > 0 {
+		days := make([]string, len(r.byDay))
+		for i, wd := range r.byDay {
+			days[i] = weekdayNames[wd.weekday]
+			if wd.n != 0 {
+				days[i] = strconv.Itoa(wd.n) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	for _, by := range []struct {
+		name   string
+		values []int
+	}{
+		{"BYHOUR", r.byHour},
+		{"BYMINUTE", r.byMinute},
+		{"BYSECOND", r.bySecond},
+	} {
+		if len(by.values) > 0 {
+			parts = append(parts, by.name+"="+joinInts(by.values))
+		}
+	}
+
+	if r.weekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.weekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// String describes the recurrence with the DTSTART, RRULE, EXDATE and RDATE
+// lines of RFC 5545.
+func (r *RRule) String() string {
+	lines := []string{"DTSTART" + icalDateTime(r.start)}
+	if rule := r.Rule(); rule != "" {
+		lines = append(lines, "RRULE:"+rule)
+	}
+
+	for _, exDate := range r.exDates {
+		lines = append(lines, "EXDATE"+icalDateTime(exDate))
+	}
+
+	for _, rDate := range r.rDates {
+		lines = append(lines, "RDATE"+icalDateTime(rDate))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Next returns the first occurrence strictly after after.
+func (r *RRule) Next(after time.Time) (next time.Time, ok bool) {
+	next, ok = r.nextRule(after)
+	for _, rDate := range r.rDates {
+		if !rDate.After(after) || r.isExcluded(rDate) {
+			continue
+		}
+
+		if !ok || rDate.Before(next) {
+			next, ok = rDate, true
+		}
+		break
+	}
+	return
+}
+
+func (r *RRule) isExcluded(dateTime time.Time) bool {
+	for _, exDate := range r.exDates {
+		if exDate.Equal(dateTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RRule) nextRule(after time.Time) (next time.Time, ok bool) {
+	if r.freq == 0 {
+		if r.start.After(after) && !r.isExcluded(r.start) {
+			return r.start, true
+		}
+		return
+	}
+
+	period, count := 0, 0
+	if r.count == 0 {
+		period = r.skip(after)
+	}
+
+	horizon := after
+	if r.start.After(horizon) {
+		horizon = r.start
+	}
+	horizon = horizon.AddDate(rruleMaxYears, 0, 0)
+
+	for empty := 0; empty < rruleMaxEmptyPeriods; period++ {
+		candidates := r.candidates(period)
+		if len(candidates) == 0 {
+			if r.periodStart(period).After(horizon) {
+				return
+			}
+
+			empty++
+			continue
+		}
+
+		empty = 0
+		for _, candidate := range candidates {
+			if candidate.Before(r.start) {
+				continue
+			}
+
+			if !r.until.IsZero() && candidate.After(r.until) {
+				return
+			}
+
+			count++
+			if r.count > 0 && count > r.count {
+				return
+			}
+
+			if candidate.After(after) && !r.isExcluded(candidate) {
+				return candidate, true
+			}
+		}
+	}
+	return
+}
+
+// skip returns a period starting before after, so the periods before it
+// are not generated again.
+func (r *RRule) skip(after time.Time) (period int) {
+	start := r.periodStart(0)
+	if !after.After(start) {
+		return
+	}
+
+	switch r.freq {
+	case FrequencyYearly:
+		period = (after.Year() - start.Year()) / r.interval
+	case FrequencyMonthly:
+		period = ((after.Year()-start.Year())*12 + int(after.Month()-start.Month())) / r.interval
+	default:
+		period = int(after.Sub(start) / (r.unit() * time.Duration(r.interval)))
+	}
+
+	if period--; period < 0 {
+		period = 0
+	}
+	return
+}
+
+func (r *RRule) unit() time.Duration {
+	switch r.freq {
+	case FrequencySecondly:
+		return time.Second
+	case FrequencyMinutely:
+		return time.Minute
+	case FrequencyHourly:
+		return time.Hour
+	case FrequencyWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+func (r *RRule) periodStart(period int) time.Time {
+	var (
+		s     = r.start
+		step  = period * r.interval
+		y, m  = s.Year(), s.Month()
+		d     = s.Day()
+		where = s.Location()
+	)
+
+	switch r.freq {
+	case FrequencyYearly:
+		return time.Date(y+step, time.January, 1, 0, 0, 0, 0, where)
+	case FrequencyMonthly:
+		return time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, where)
+	case FrequencyWeekly:
+		offset := (int(s.Weekday()) - int(r.weekStart) + 7) % 7
+		return time.Date(y, m, d-offset+7*step, 0, 0, 0, 0, where)
+	case FrequencyDaily:
+		return time.Date(y, m, d+step, 0, 0, 0, 0, where)
+	case FrequencyHourly:
+		base := s.Add(-time.Duration(s.Minute())*time.Minute - time.Duration(s.Second())*time.Second)
+		return base.Add(time.Duration(step) * time.Hour)
+	case FrequencyMinutely:
+		base := s.Add(-time.Duration(s.Second()) * time.Second)
+		return base.Add(time.Duration(step) * time.Minute)
+	default:
+		return s.Add(time.Duration(step) * time.Second)
+	}
+}
+
+func (r *RRule) candidates(period int) (res []time.Time) {
+	start := r.periodStart(period)
+	switch r.freq {
+	case FrequencyYearly, FrequencyMonthly, FrequencyWeekly, FrequencyDaily:
+		for _, day := range r.days(start) {
+			res = append(res, r.times(day)...)
+		}
+	default:
+		res = r.instants(start)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Before(res[j])
+	})
+	return
+}
+
+func (r *RRule) days(start time.Time) (res []time.Time) {
+	var end time.Time
+	switch r.freq {
+	case FrequencyYearly:
+		end = start.AddDate(1, 0, 0)
+	case FrequencyMonthly:
+		end = start.AddDate(0, 1, 0)
+	case FrequencyWeekly:
+		end = start.AddDate(0, 0, 7)
+	default:
+		end = start.AddDate(0, 0, 1)
+	}
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if r.matchDay(day) {
+			res = append(res, day)
+		}
+	}
+	return
+}
+
+func (r *RRule) matchDay(day time.Time) bool {
+	if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(day.Month())) {
+		return false
+	}
+
+	if len(r.byMonthDay) > 0 && !r.matchMonthDay(day) {
+		return false
+	}
+
+	if len(r.byDay) > 0 {
+		return r.matchWeekday(day)
+	}
+
+	if len(r.byMonthDay) > 0 {
+		return true
+	}
+
+	switch r.freq {
+	case FrequencyYearly:
+		if len(r.byMonth) > 0 {
+			return day.Day() == r.start.Day()
+		}
+		return day.Month() == r.start.Month() && day.Day() == r.start.Day()
+	case FrequencyMonthly:
+		return day.Day() == r.start.Day()
+	case FrequencyWeekly:
+		return day.Weekday() == r.start.Weekday()
+	default:
+		return true
+	}
+}
+
+func (r *RRule) matchMonthDay(day time.Time) bool {
+	total := daysIn(day.Year(), day.Month())
+	for _, monthDay := range r.byMonthDay {
+		if monthDay == day.Day() || monthDay == day.Day()-total-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWeekday matches BYDAY, the ordinal of a weekday counts within the
+// month for MONTHLY rules and YEARLY rules having BYMONTH, within the year
+// for other YEARLY rules.
+func (r *RRule) matchWeekday(day time.Time) bool {
+	inYear := r.freq == FrequencyYearly && len(r.byMonth) == 0
+	for _, wd := range r.byDay {
+		if wd.weekday != day.Weekday() {
+			continue
+		}
+
+		if wd.n == 0 || (r.freq != FrequencyYearly && r.freq != FrequencyMonthly) {
+			return true
+		}
+
+		index, total := day.Day(), daysIn(day.Year(), day.Month())
+		if inYear {
+			index, total = day.YearDay(), daysIn(day.Year(), 0)
+		}
+
+		if wd.n > 0 && (index-1)/7+1 == wd.n {
+			return true
+		}
+
+		if wd.n < 0 && -((total-index)/7+1) == wd.n {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RRule) times(day time.Time) (res []time.Time) {
+	hours := orInts(r.byHour, r.start.Hour())
+	minutes := orInts(r.byMinute, r.start.Minute())
+	seconds := orInts(r.bySecond, r.start.Second())
+	for _, hour := range hours {
+		for _, minute := range minutes {
+			for _, second := range seconds {
+				res = append(res, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, day.Location()))
+			}
+		}
+	}
+	return
+}
+
+func (r *RRule) instants(start time.Time) (res []time.Time) {
+	minutes, seconds := []int{start.Minute()}, []int{start.Second()}
+	switch r.freq {
+	case FrequencyHourly:
+		minutes, seconds = orInts(r.byMinute, r.start.Minute()), orInts(r.bySecond, r.start.Second())
+	case FrequencyMinutely:
+		seconds = orInts(r.bySecond, r.start.Second())
+	}
+
+	for _, minute := range minutes {
+		for _, second := range seconds {
+			instant := start.Add(time.Duration(minute-start.Minute())*time.Minute + time.Duration(second-start.Second())*time.Second)
+			if !r.matchInstant(instant) {
+				continue
+			}
+
+			res = append(res, instant)
+		}
+	}
+	return
+}
+
+func (r *RRule) matchInstant(instant time.Time) bool {
+	if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(instant.Month())) {
+		return false
+	}
+
+	if len(r.byMonthDay) > 0 && !r.matchMonthDay(instant) {
+		return false
+	}
+
+	if len(r.byDay) > 0 && !r.matchWeekday(instant) {
+		return false
+	}
+
+	if len(r.byHour) > 0 && !containsInt(r.byHour, instant.Hour()) {
+		return false
+	}
+
+	if r.freq == FrequencySecondly && len(r.byMinute) > 0 && !containsInt(r.byMinute, instant.Minute()) {
+		return false
+	}
+
+	if r.freq == FrequencySecondly && len(r.bySecond) > 0 && !containsInt(r.bySecond, instant.Second()) {
+		return false
+	}
+
+	if r.freq == FrequencyMinutely && len(r.byMinute) > 0 && !containsInt(r.byMinute, instant.Minute()) {
+		return false
+	}
+	return true
+}
+
+// daysIn returns the days of month, or of the year when month is 0.
+func daysIn(year int, month time.Month) int {
+	if month == 0 {
+		return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func orInts(values []int, value int) []int {
+	if len(values) > 0 {
+		return values
+	}
+
+	return []int{value}
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinInts(values []int) string {
+	res := make([]string, len(values))
+	for i, v := range values {
+		res[i] = strconv.Itoa(v)
+	}
+	return strings.Join(res, ",")
+}
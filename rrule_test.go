@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func occurrences(r *RRule, total int) (res []time.Time) {
+	after := r.Start().Add(-time.Second)
+	for i := 0; i < total; i++ {
+		next, ok := r.Next(after)
+		if !ok {
+			break
+		}
+
+		res = append(res, next)
+		after = next
+	}
+	return
+}
+
+func TestRRule(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+	date := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, location)
+	}
+	start := date(2022, time.January, 3, 9, 0)
+
+	t.Run("Positive Case", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			start    time.Time
+			rule     string
+			expected []time.Time
+		}{
+			{
+				name:     "Daily",
+				start:    start,
+				rule:     "FREQ=DAILY;COUNT=3",
+				expected: []time.Time{start, date(2022, time.January, 4, 9, 0), date(2022, time.January, 5, 9, 0)},
+			},
+			{
+				name:     "Daily until",
+				start:    start,
+				rule:     "FREQ=DAILY;UNTIL=20220105T140000Z",
+				expected: []time.Time{start, date(2022, time.January, 4, 9, 0), date(2022, time.January, 5, 9, 0)},
+			},
+			{
+				name:  "Weekly by day",
+				start: start,
+				rule:  "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+				expected: []time.Time{
+					start, date(2022, time.January, 5, 9, 0),
+					date(2022, time.January, 10, 9, 0), date(2022, time.January, 12, 9, 0),
+				},
+			},
+			{
+				name:     "Every other week",
+				start:    start,
+				rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;COUNT=2",
+				expected: []time.Time{date(2022, time.January, 4, 9, 0), date(2022, time.January, 18, 9, 0)},
+			},
+			{
+				name:  "Last friday of month",
+				start: start,
+				rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+				expected: []time.Time{
+					date(2022, time.January, 28, 9, 0), date(2022, time.February, 25, 9, 0), date(2022, time.March, 25, 9, 0),
+				},
+			},
+			{
+				name:  "Monthly skips short months",
+				start: start,
+				rule:  "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3",
+				expected: []time.Time{
+					date(2022, time.January, 31, 9, 0), date(2022, time.March, 31, 9, 0), date(2022, time.May, 31, 9, 0),
+				},
+			},
+			{
+				name:     "Leap day",
+				start:    start,
+				rule:     "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29;COUNT=2",
+				expected: []time.Time{date(2024, time.February, 29, 9, 0), date(2028, time.February, 29, 9, 0)},
+			},
+			{
+				name:     "Nth weekday of year",
+				start:    start,
+				rule:     "FREQ=YEARLY;BYDAY=20MO;COUNT=1",
+				expected: []time.Time{date(2022, time.May, 16, 9, 0)},
+			},
+			{
+				name:     "Hourly",
+				start:    start,
+				rule:     "FREQ=HOURLY;INTERVAL=6;COUNT=3",
+				expected: []time.Time{start, date(2022, time.January, 3, 15, 0), date(2022, time.January, 3, 21, 0)},
+			},
+			{
+				name:  "Minutely by hour",
+				start: start,
+				rule:  "FREQ=MINUTELY;INTERVAL=15;BYHOUR=9;COUNT=5",
+				expected: []time.Time{
+					start, date(2022, time.January, 3, 9, 15), date(2022, time.January, 3, 9, 30),
+					date(2022, time.January, 3, 9, 45), date(2022, time.January, 4, 9, 0),
+				},
+			},
+			{
+				name:     "Daily across daylight saving time",
+				start:    date(2022, time.March, 12, 9, 0),
+				rule:     "FREQ=DAILY;COUNT=2",
+				expected: []time.Time{date(2022, time.March, 12, 9, 0), date(2022, time.March, 13, 9, 0)},
+			},
+			{
+				name:     "Only start",
+				start:    start,
+				rule:     "",
+				expected: []time.Time{start},
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				r, err := ParseRRule(testCase.start, testCase.rule)
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.expected, occurrences(r, 10))
+
+				again, err := ParseRRule(testCase.start, r.Rule())
+				assert.Nil(t, err)
+				assert.Equal(t, r.String(), again.String())
+			})
+		}
+
+		t.Run("Exclude and include", func(t *testing.T) {
+			r, err := ParseRRule(start, "FREQ=DAILY;COUNT=3")
+			assert.Nil(t, err)
+			r.Exclude(date(2022, time.January, 4, 9, 0))
+			r.Include(date(2022, time.January, 10, 9, 0))
+
+			assert.Equal(t, []time.Time{start, date(2022, time.January, 5, 9, 0), date(2022, time.January, 10, 9, 0)}, occurrences(r, 10))
+			assert.Equal(t, "DTSTART;TZID=America/New_York:20220103T090000\n"+
+				"RRULE:FREQ=DAILY;COUNT=3\n"+
+				"EXDATE;TZID=America/New_York:20220104T090000\n"+
+				"RDATE;TZID=America/New_York:20220110T090000", r.String())
+		})
+
+		t.Run("Next far after start", func(t *testing.T) {
+			r, err := ParseRRule(start, "FREQ=DAILY")
+			assert.Nil(t, err)
+			next, ok := r.Next(date(2030, time.June, 15, 12, 0))
+			assert.True(t, ok)
+			assert.Equal(t, date(2030, time.June, 16, 9, 0), next)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		for _, rule := range []string{"FREQ=FOO", "FREQ=DAILY;COUNT=0", "FREQ=DAILY;BYSETPOS=1", "FREQ=DAILY;INTERVAL=x", "FREQ=WEEKLY;BYDAY=XX", "COUNT=2", "FREQ"} {
+			_, err := ParseRRule(start, rule)
+			assert.ErrorIs(t, err, ErrRRuleFormat, rule)
+		}
+
+		r, err := ParseRRule(start, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+		assert.Nil(t, err)
+		_, ok := r.Next(start)
+		assert.False(t, ok)
+	})
+}
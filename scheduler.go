@@ -3,6 +3,8 @@ package scheduler
 import (
 	"context"
 	"io"
+	"log/slog"
+	"math"
 	"sync"
 	"time"
 )
@@ -10,24 +12,81 @@ import (
 type FnScheduler func(ctx context.Context)
 
 const (
-	defaultUTCTimeZone = "UTC"
+	defaultUTCTimeZone  = "UTC"
+	defaultPollInterval = time.Second
+	defaultClaimLimit   = 100
+
+	defaultExecutionLockTTL = time.Minute
+
+	listBatchSize = 1000
 )
 
 type Scheduler struct {
 	schedulers      map[string]*detailScheduler
-	schedulersSlice detailSchedulers
+	schedulersIndex detailSchedulers
+	handlers        map[string]FnScheduler
+	listeners       []Listener
+	middlewares     []Middleware
 	mutex           sync.RWMutex
 	config          Config
 	locationTZ      *time.Location
+	done            chan struct{}
+	stopOnce        sync.Once
+	owner           string
+	leased          int
+	running         int
+	executions      map[string]map[uint64]context.CancelFunc
+	executionID     uint64
+	stats           *statsListener
+	watchHub        *watchHub
+	crontabReboots  map[string]struct{}
 }
 
 type paramScheduler struct {
 	duration time.Duration
 	dateTime time.Time
+	handler  string
+	tags     []string
+	trigger  Trigger
+	paused   bool
 }
 
 type Config struct {
 	TimeZone string
+
+	// Store shares jobs added through AddHandler between every scheduler
+	// using it. Due jobs are claimed by polling every PollInterval.
+	Store        Store
+	PollInterval time.Duration
+	ClaimLimit   int
+
+	// LeaseTTL turns the scheduler into a worker of a LeaseStore: due jobs
+	// are leased for LeaseTTL, acknowledged on success and released again
+	// after LeaseRetryDelay on failure. ClaimLimit bounds the leased jobs.
+	LeaseTTL        time.Duration
+	LeaseRetryDelay time.Duration
+
+	LeaderElector LeaderElector
+	Listeners     []Listener
+	Middlewares   []Middleware
+
+	// Logger logs the decisions of the scheduler, a job started later than
+	// MisfireThreshold after its date time is logged as misfired.
+	Logger           *slog.Logger
+	MisfireThreshold time.Duration
+
+	// HistorySize and HistoryMaxAge bound the runs kept for every key.
+	HistorySize   int
+	HistoryMaxAge time.Duration
+
+	// ExecutionLocker makes an occurrence run at most once across schedulers
+	// sharing it. The lock is kept until ExecutionLockTTL elapses and renewed
+	// while the job is running when ExecutionLockRenew is set.
+	ExecutionLocker    Locker
+	ExecutionLockTTL   time.Duration
+	ExecutionLockRenew bool
+
+	RetryPolicy RetryPolicy
 }
 
 func NewScheduler(configs ...Config) *Scheduler {
@@ -36,13 +95,46 @@ func NewScheduler(configs ...Config) *Scheduler {
 		config = configs[0]
 	}
 
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+
+	if config.ClaimLimit <= 0 {
+		config.ClaimLimit = defaultClaimLimit
+	}
+
+	if config.ExecutionLockTTL <= 0 {
+		config.ExecutionLockTTL = defaultExecutionLockTTL
+	}
+
 	scheduler := &Scheduler{
-		schedulers: make(map[string]*detailScheduler),
-		mutex:      sync.RWMutex{},
-		config:     config,
+		schedulers:     make(map[string]*detailScheduler),
+		handlers:       make(map[string]FnScheduler),
+		executions:     make(map[string]map[uint64]context.CancelFunc),
+		crontabReboots: make(map[string]struct{}),
+		listeners:      append([]Listener(nil), config.Listeners...),
+		middlewares:    append([]Middleware(nil), config.Middlewares...),
+		mutex:          sync.RWMutex{},
+		config:         config,
+		done:           make(chan struct{}),
+		owner:          defaultOwner(),
+		stats:          newStatsListener(config.HistorySize, config.HistoryMaxAge),
+		watchHub:       newWatchHub(),
+	}
+
+	scheduler.listeners = append([]Listener{scheduler.stats, scheduler.watchHub}, scheduler.listeners...)
+
+	if config.Logger != nil {
+		logListener := newLogListener(config.Logger, config.MisfireThreshold)
+		scheduler.listeners = append(scheduler.listeners, logListener)
+		scheduler.middlewares = append([]Middleware{logListener.middleware}, scheduler.middlewares...)
 	}
 
 	scheduler.loadTZ()
+	if config.Store != nil {
+		go scheduler.poll()
+	}
+
 	return scheduler
 }
 
@@ -63,79 +155,182 @@ func (s *Scheduler) read(key string) (bool, *detailScheduler) {
 }
 
 func (s *Scheduler) add(key string, param *paramScheduler, fn FnScheduler) (err error) {
-	isExists, _ := s.read(key)
-	if isExists {
-		err = ErrKeyIsExists
+	ds, err := s.insert(key, param, fn)
+	if err != nil {
 		return
 	}
 
+	event := ds.toEvent()
+	s.emit(EventTypeScheduled, event)
+	return
+}
+
+func (s *Scheduler) insert(key string, param *paramScheduler, fn FnScheduler) (ds *detailScheduler, err error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	ds := &detailScheduler{
-		key: key,
-		timer: time.AfterFunc(param.duration, func() {
-			fn(context.Background())
-			_, ds := s.read(key)
-			s.deleteScheduler(key)
-			s.deleteSchedulerSlice(ds)
-		}),
+	if _, isExists := s.schedulers[key]; isExists {
+		err = ErrKeyIsExists
+		return
+	}
+
+	ds = &detailScheduler{
+		key:      key,
 		dateTime: param.dateTime,
-		idx:      s.schedulersSlice.Total(),
+		handler:  param.handler,
+		tags:     param.tags,
+		trigger:  param.trigger,
+		fn:       fn,
+	}
+
+	// A paused job keeps its duration until it is resumed, its timer is
+	// stopped before it may fire.
+	duration := param.duration
+	if param.paused {
+		ds.paused, ds.remaining, duration = true, param.duration, math.MaxInt64
 	}
 
-	s.schedulersSlice.Add(ds)
+	ds.timer = time.AfterFunc(duration, func() {
+		s.fire(ds, fn)
+	})
+	if param.paused {
+		ds.timer.Stop()
+	}
+
+	s.schedulersIndex.Add(ds)
 	s.schedulers[key] = ds
 
 	return
 }
 
-func (s *Scheduler) deleteScheduler(key string) {
+func (s *Scheduler) fire(ds *detailScheduler, fn FnScheduler) {
+	s.mutex.Lock()
+	event := ds.toEvent()
+	event.Attempt = 1
+	ds.running = true
+	ds.runs++
+	s.mutex.Unlock()
+
+	if s.isLeader() {
+		s.executeRetrying(event, fn)
+	} else {
+		event.Err = ErrSchedulerIsNotLeader
+		s.emit(EventTypeSkipped, event)
+	}
+
+	s.mutex.Lock()
+	ds.running = false
+	s.mutex.Unlock()
+
+	if ds.trigger != nil && s.rearm(ds, event.DateTime) {
+		return
+	}
+
+	if s.delete(ds) {
+		s.emit(EventTypeRemoved, event)
+	}
+}
+
+func (s *Scheduler) isLeader() bool {
+	return s.config.LeaderElector == nil || s.config.LeaderElector.IsLeader()
+}
+
+func (s *Scheduler) delete(ds *detailScheduler) bool {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	delete(s.schedulers, key)
+	if s.schedulers[ds.key] != ds {
+		return false
+	}
+
+	delete(s.schedulers, ds.key)
+	s.schedulersIndex.Remove(ds)
+	return true
 }
 
-func (s *Scheduler) deleteSchedulerSlice(ds *detailScheduler) {
+func (s *Scheduler) remove(key string) (ds *detailScheduler, err error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.schedulersSlice.Remove(ds.idx)
+	ds, isExists := s.schedulers[key]
+	if !isExists {
+		err = ErrKeyIsNotExists
+		return
+	}
+
+	ds.timer.Stop()
+	delete(s.schedulers, key)
+	s.schedulersIndex.Remove(ds)
+	return
 }
 
 func (s *Scheduler) reschedule(key string, param *paramScheduler) (err error) {
 	isExists, ds := s.read(key)
 
+	if !isExists && s.config.Store != nil {
+		err = s.config.Store.Reschedule(context.Background(), key, param.dateTime)
+		if err == nil {
+			s.emit(EventTypeRescheduled, Event{Key: key, DateTime: param.dateTime})
+		}
+		return
+	}
+
 	if !isExists {
 		err = ErrKeyIsNotExists
 		return
 	}
 
+	s.mutex.Lock()
+	event := ds.toEvent()
+	event.PreviousDateTime, event.DateTime = ds.dateTime, param.dateTime
+	s.schedulersIndex.Move(ds, param.dateTime)
+	ds.paused, ds.pauseOnRearm = false, false
 	ds.timer.Reset(param.duration)
+	s.mutex.Unlock()
+
+	s.emit(EventTypeRescheduled, event)
 	return
 }
 
 func (s *Scheduler) cancel(key string) (err error) {
-	isExists, ds := s.read(key)
-	if !isExists {
-		err = ErrKeyIsNotExists
+	isExists, _ := s.read(key)
+	if !isExists && s.config.Store != nil {
+		err = s.config.Store.Cancel(context.Background(), key)
+		if err == nil {
+			s.emit(EventTypeCancelled, Event{Key: key})
+		}
 		return
 	}
 
-	ds.timer.Stop()
-	s.deleteScheduler(key)
-	s.deleteSchedulerSlice(ds)
+	ds, err := s.remove(key)
+	if err != nil {
+		return
+	}
+
+	s.interrupt(key)
+	event := ds.toEvent()
+	s.emit(EventTypeCancelled, event)
 	return
 }
 
 func (s *Scheduler) replace(key string, param *paramScheduler, fn FnScheduler) (err error) {
-	err = s.cancel(key)
+	previous, err := s.remove(key)
+	if err != nil {
+		return
+	}
+
+	if param.tags == nil {
+		param.tags = previous.tags
+	}
+
+	ds, err := s.insert(key, param, fn)
 	if err != nil {
 		return
 	}
 
-	err = s.add(key, param, fn)
+	event := ds.toEvent()
+	event.PreviousDateTime = previous.dateTime
+	s.emit(EventTypeReplaced, event)
 	return
 }
 
@@ -159,27 +354,33 @@ func (s *Scheduler) fromDurationToDateTime(duration time.Duration) time.Time {
 }
 
 func (s *Scheduler) toResponseScheduler() (res []*ResponseScheduler) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	return toResponseSchedulers(s.jobInfos())
+}
 
-	for i := 0; i < len(s.schedulersSlice); i++ {
+func toResponseSchedulers(infos []*JobInfo) (res []*ResponseScheduler) {
+	for _, info := range infos {
 		res = append(res, &ResponseScheduler{
-			Key:  s.schedulersSlice[i].key,
-			Time: s.schedulersSlice[i].dateTime,
+			Key:     info.Key,
+			Time:    info.DateTime,
+			Handler: info.Handler,
+			Status:  info.Status,
+			Tags:    info.Tags,
+
+			Recurrence: info.Recurrence,
 		})
 	}
-
 	return
 }
 
-func (s *Scheduler) Add(key string, duration time.Duration, fn FnScheduler) (err error) {
+func (s *Scheduler) Add(key string, duration time.Duration, fn FnScheduler, opts ...JobOption) (err error) {
 	return s.add(key, &paramScheduler{
 		duration: duration,
 		dateTime: s.fromDurationToDateTime(duration),
+		tags:     jobTags(opts),
 	}, fn)
 }
 
-func (s *Scheduler) AddDate(key string, dateTime time.Time, fn FnScheduler) (err error) {
+func (s *Scheduler) AddDate(key string, dateTime time.Time, fn FnScheduler, opts ...JobOption) (err error) {
 	duration, err := s.subtractDateTime(dateTime)
 	if err != nil {
 		return
@@ -188,9 +389,12 @@ func (s *Scheduler) AddDate(key string, dateTime time.Time, fn FnScheduler) (err
 	return s.add(key, &paramScheduler{
 		duration: duration,
 		dateTime: dateTime.In(s.locationTZ),
+		tags:     jobTags(opts),
 	}, fn)
 }
 
+// Cancel removes the job of key and cancels the context of its running
+// executions.
 func (s *Scheduler) Cancel(key string) (err error) {
 	err = s.cancel(key)
 	return
@@ -215,15 +419,18 @@ func (s *Scheduler) RescheduleDateTime(key string, dateTime time.Time) (err erro
 	})
 }
 
-func (s *Scheduler) Replace(key string, duration time.Duration, fn FnScheduler) (err error) {
+// Replace replaces the job of key by a job running fn after duration, the
+// tags of the job are kept when opts has none.
+func (s *Scheduler) Replace(key string, duration time.Duration, fn FnScheduler, opts ...JobOption) (err error) {
 	err = s.replace(key, &paramScheduler{
 		duration: duration,
 		dateTime: s.fromDurationToDateTime(duration),
+		tags:     jobTags(opts),
 	}, fn)
 	return
 }
 
-func (s *Scheduler) ReplaceDateTime(key string, dateTime time.Time, fn FnScheduler) (err error) {
+func (s *Scheduler) ReplaceDateTime(key string, dateTime time.Time, fn FnScheduler, opts ...JobOption) (err error) {
 	duration, err := s.subtractDateTime(dateTime)
 	if err != nil {
 		return
@@ -232,17 +439,20 @@ func (s *Scheduler) ReplaceDateTime(key string, dateTime time.Time, fn FnSchedul
 	err = s.replace(key, &paramScheduler{
 		duration: duration,
 		dateTime: dateTime.In(s.locationTZ),
+		tags:     jobTags(opts),
 	}, fn)
 	return
 }
 
+// List renders every job ordered by date time and key. The jobs are read in
+// batches after the last job rendered so the scheduler is not locked while
+// rendering, a job added, removed or rescheduled meanwhile may be missed or
+// rendered twice.
 func (s *Scheduler) List(w io.Writer, lcs ...ListConverter) (n int, err error) {
-	responseScheduler := s.toResponseScheduler()
 	lc := NewDefaultResponse()
 	if len(lcs) > 0 {
 		lc = lcs[0]
 	}
 
-	bytes, err := lc.Convert(responseScheduler)
-	return w.Write(bytes)
+	return writeList(w, s.iterateJobs(), lc)
 }
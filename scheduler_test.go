@@ -649,6 +649,11 @@ func TestListConverter(t *testing.T) {
 			return n > 0
 		})
 
+		type jsonData struct {
+			Key      string    `json:"key"`
+			DateTime time.Time `json:"date_time"`
+		}
+
 		responseSchedulers := schedule.toResponseScheduler()
 		structSchedulers := make([]jsonData, 0)
 		for i := 0; i < len(responseSchedulers); i++ {
@@ -663,3 +668,44 @@ func TestListConverter(t *testing.T) {
 		assert.Equal(t, buf.Bytes(), actualOutput)
 	})
 }
+
+func TestHandlerScheduler(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Add key bound to a registered handler", func(t *testing.T) {
+			t.Parallel()
+			var wg sync.WaitGroup
+			schedule := NewScheduler()
+			wg.Add(1)
+			err := schedule.RegisterHandler("handler", func(ctx context.Context) {
+				wg.Done()
+			})
+			assert.Nil(t, err)
+			err = schedule.AddHandler("add#1", 100*time.Millisecond, "handler")
+			assert.Nil(t, err)
+			isExists, ds := schedule.read("add#1")
+			assert.Equal(t, isExists, true)
+			assert.Equal(t, ds.handler, "handler")
+			wg.Wait()
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Register handler twice", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			err := schedule.RegisterHandler("handler", fn)
+			assert.Nil(t, err)
+			err = schedule.RegisterHandler("handler", fn)
+			assert.Equal(t, err, ErrHandlerIsExists)
+		})
+
+		t.Run("Add key bound to an unknown handler", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			err := schedule.AddDateHandler("add#1", time.Now().UTC().Add(time.Minute), "handler")
+			assert.Equal(t, err, ErrHandlerIsNotExists)
+			isExists, _ := schedule.read("add#1")
+			assert.Equal(t, isExists, false)
+		})
+	})
+}
@@ -0,0 +1,506 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+const (
+	// snapshotVersion 2 adds the tags and the paused state of the jobs and
+	// version 3 their trigger, older snapshots are still restored.
+	snapshotVersion    = 3
+	snapshotMinVersion = 1
+
+	snapshotTriggerCron     = "cron"
+	snapshotTriggerInterval = "interval"
+	snapshotTriggerRRule    = "rrule"
+)
+
+var (
+	snapshotMagic = []byte("GSS")
+)
+
+type snapshot struct {
+	Version int            `json:"version"`
+	Jobs    []*snapshotJob `json:"jobs"`
+}
+
+type snapshotJob struct {
+	Key      string    `json:"key"`
+	Handler  string    `json:"handler"`
+	DateTime time.Time `json:"date_time"`
+	TimeZone string    `json:"time_zone"`
+	Tags     []string  `json:"tags,omitempty"`
+	// Remaining is the duration left to a paused job when it is resumed.
+	Paused    bool             `json:"paused,omitempty"`
+	Remaining time.Duration    `json:"remaining,omitempty"`
+	Trigger   *snapshotTrigger `json:"trigger,omitempty"`
+}
+
+// snapshotTrigger is the recurrence of a job added with a Cron, Interval or
+// RRule trigger. Spec is the cron spec or the RRULE value, Start, Interval,
+// ExDates and RDates are read in TimeZone.
+type snapshotTrigger struct {
+	Kind     string        `json:"kind"`
+	Spec     string        `json:"spec,omitempty"`
+	TimeZone string        `json:"time_zone"`
+	Start    time.Time     `json:"start,omitempty"`
+	Interval time.Duration `json:"interval,omitempty"`
+	ExDates  []time.Time   `json:"ex_dates,omitempty"`
+	RDates   []time.Time   `json:"r_dates,omitempty"`
+}
+
+// toSnapshotTrigger returns the recurrence of trigger, ok is false for a
+// Trigger implemented outside of the package.
+func toSnapshotTrigger(trigger Trigger) (res *snapshotTrigger, ok bool) {
+	switch t := trigger.(type) {
+	case nil:
+		return nil, true
+	case *Cron:
+		return &snapshotTrigger{Kind: snapshotTriggerCron, Spec: t.spec, TimeZone: t.location.String()}, true
+	case *Interval:
+		return &snapshotTrigger{
+			Kind:     snapshotTriggerInterval,
+			TimeZone: t.start.Location().String(),
+			Start:    t.start,
+			Interval: t.interval,
+		}, true
+	case *RRule:
+		return &snapshotTrigger{
+			Kind:     snapshotTriggerRRule,
+			Spec:     t.Rule(),
+			TimeZone: t.start.Location().String(),
+			Start:    t.start,
+			ExDates:  t.ExDates(),
+			RDates:   t.RDates(),
+		}, true
+	}
+	return nil, false
+}
+
+func (t *snapshotTrigger) toTrigger() (trigger Trigger, err error) {
+	location, err := time.LoadLocation(t.TimeZone)
+	if err != nil {
+		return
+	}
+
+	in := func(dateTimes []time.Time) (res []time.Time) {
+		for _, dateTime := range dateTimes {
+			res = append(res, dateTime.In(location))
+		}
+		return
+	}
+
+	switch t.Kind {
+	case snapshotTriggerCron:
+		return ParseCron(t.Spec, location)
+	case snapshotTriggerInterval:
+		return NewInterval(t.Start.In(location), t.Interval), nil
+	case snapshotTriggerRRule:
+		rrule, err := ParseRRule(t.Start.In(location), t.Spec)
+		if err != nil {
+			return nil, err
+		}
+
+		rrule.Exclude(in(t.ExDates)...)
+		rrule.Include(in(t.RDates)...)
+		return rrule, nil
+	}
+	return nil, fmt.Errorf("unknown trigger %q", t.Kind)
+}
+
+type RestoreOption struct {
+	Strategy RestoreStrategy
+}
+
+type RestoreResult struct {
+	Restored  []string
+	Conflicts []string
+	Skipped   []string
+}
+
+// toSnapshot returns the jobs bound to a handler, the jobs added with a
+// function or a Trigger implemented outside of the package cannot be
+// restored so they are left out.
+func (s *Scheduler) toSnapshot() *snapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	snap := &snapshot{Version: snapshotVersion}
+	for _, ds := range s.schedulers {
+		trigger, ok := toSnapshotTrigger(ds.trigger)
+		if ds.handler == "" || !ok {
+			continue
+		}
+
+		job := &snapshotJob{
+			Key:      ds.key,
+			Handler:  ds.handler,
+			DateTime: ds.dateTime,
+			TimeZone: ds.dateTime.Location().String(),
+			Tags:     ds.tags,
+			Paused:   ds.paused,
+			Trigger:  trigger,
+		}
+		if ds.paused {
+			job.Remaining = ds.remaining
+		}
+		snap.Jobs = append(snap.Jobs, job)
+	}
+
+	sort.Slice(snap.Jobs, func(i, j int) bool {
+		return snap.Jobs[i].Key < snap.Jobs[j].Key
+	})
+	return snap
+}
+
+func (s *Scheduler) Snapshot(w io.Writer, formats ...SnapshotFormat) (err error) {
+	snap := s.toSnapshot()
+	format := SnapshotFormatJSON
+	if len(formats) > 0 {
+		format = formats[0]
+	}
+
+	switch format {
+	case SnapshotFormatJSON:
+		err = json.NewEncoder(w).Encode(snap)
+	case SnapshotFormatBinary:
+		err = encodeBinarySnapshot(w, snap)
+	default:
+		err = ErrSnapshotFormat
+	}
+	return
+}
+
+func (s *Scheduler) Restore(r io.Reader, opts ...RestoreOption) (res *RestoreResult, err error) {
+	opt := RestoreOption{}
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	snap, err := decodeSnapshot(r)
+	if err != nil {
+		return
+	}
+
+	res = &RestoreResult{}
+	fns := make(map[string]FnScheduler)
+	for _, job := range snap.Jobs {
+		fn, errHandler := s.handler(job.Handler)
+		if errHandler != nil {
+			if opt.Strategy == RestoreStrategyFail {
+				err = fmt.Errorf("%w: %s of key %s", errHandler, job.Handler, job.Key)
+				return
+			}
+
+			res.Skipped = append(res.Skipped, job.Key)
+			continue
+		}
+
+		if isExists, _ := s.read(job.Key); isExists {
+			res.Conflicts = append(res.Conflicts, job.Key)
+			if opt.Strategy != RestoreStrategyOverwrite {
+				continue
+			}
+		}
+
+		fns[job.Key] = fn
+	}
+
+	if opt.Strategy == RestoreStrategyFail && len(res.Conflicts) > 0 {
+		err = ErrKeyIsExists
+		return
+	}
+
+	for _, job := range snap.Jobs {
+		fn, isExists := fns[job.Key]
+		if !isExists {
+			continue
+		}
+
+		if err = s.restoreJob(job, fn); err != nil {
+			return
+		}
+
+		res.Restored = append(res.Restored, job.Key)
+	}
+	return
+}
+
+func (s *Scheduler) restoreJob(job *snapshotJob, fn FnScheduler) (err error) {
+	location, err := time.LoadLocation(job.TimeZone)
+	if err != nil {
+		location = s.locationTZ
+	}
+
+	param := &paramScheduler{
+		duration: time.Until(job.DateTime),
+		dateTime: job.DateTime.In(location),
+		handler:  job.Handler,
+		tags:     job.Tags,
+		paused:   job.Paused,
+	}
+
+	if job.Paused {
+		param.duration = job.Remaining
+	}
+
+	if job.Trigger != nil {
+		if param.trigger, err = job.Trigger.toTrigger(); err != nil {
+			return fmt.Errorf("%w: trigger of key %s: %v", ErrSnapshotFormat, job.Key, err)
+		}
+	}
+
+	if param.duration < 0 {
+		param.duration = 0
+	}
+
+	if isExists, _ := s.read(job.Key); isExists {
+		return s.replace(job.Key, param, fn)
+	}
+
+	return s.add(job.Key, param, fn)
+}
+
+func decodeSnapshot(r io.Reader) (snap *snapshot, err error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(snapshotMagic))
+	if err == nil && bytes.Equal(magic, snapshotMagic) {
+		snap, err = decodeBinarySnapshot(br)
+	} else {
+		snap = &snapshot{}
+		err = json.NewDecoder(br).Decode(snap)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrSnapshotFormat, err)
+		return
+	}
+
+	if snap.Version < snapshotMinVersion || snap.Version > snapshotVersion {
+		err = ErrSnapshotVersion
+	}
+	return
+}
+
+func encodeBinarySnapshot(w io.Writer, snap *snapshot) (err error) {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, binary.MaxVarintLen64)
+	writeUvarint := func(v uint64) {
+		_, _ = bw.Write(buf[:binary.PutUvarint(buf, v)])
+	}
+	writeVarint := func(v int64) {
+		_, _ = bw.Write(buf[:binary.PutVarint(buf, v)])
+	}
+	writeString := func(v string) {
+		writeUvarint(uint64(len(v)))
+		_, _ = bw.WriteString(v)
+	}
+
+	_, _ = bw.Write(snapshotMagic)
+	writeUvarint(uint64(snap.Version))
+	writeUvarint(uint64(len(snap.Jobs)))
+	for _, job := range snap.Jobs {
+		writeString(job.Key)
+		writeString(job.Handler)
+		writeString(job.TimeZone)
+		_, _ = bw.Write(buf[:binary.PutVarint(buf, job.DateTime.UnixNano())])
+		writeUvarint(uint64(len(job.Tags)))
+		for _, tag := range job.Tags {
+			writeString(tag)
+		}
+
+		if job.Paused {
+			_ = bw.WriteByte(1)
+			_, _ = bw.Write(buf[:binary.PutVarint(buf, int64(job.Remaining))])
+		} else {
+			_ = bw.WriteByte(0)
+		}
+
+		if job.Trigger == nil {
+			writeString("")
+			continue
+		}
+
+		writeString(job.Trigger.Kind)
+		writeString(job.Trigger.Spec)
+		writeString(job.Trigger.TimeZone)
+		writeVarint(job.Trigger.Start.UnixNano())
+		writeVarint(int64(job.Trigger.Interval))
+		for _, dateTimes := range [][]time.Time{job.Trigger.ExDates, job.Trigger.RDates} {
+			writeUvarint(uint64(len(dateTimes)))
+			for _, dateTime := range dateTimes {
+				writeVarint(dateTime.UnixNano())
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// decodeBinarySnapshot reads the whole snapshot first, so the length of a
+// string is checked against the remaining input before it is allocated.
+func decodeBinarySnapshot(r io.Reader) (snap *snapshot, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+
+	if !bytes.HasPrefix(data, snapshotMagic) {
+		err = errors.New("missing magic")
+		return
+	}
+
+	br := bytes.NewReader(data[len(snapshotMagic):])
+	readString := func() (v string, err error) {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return
+		}
+
+		if n > uint64(br.Len()) {
+			err = fmt.Errorf("string of %d bytes exceeds the %d remaining bytes", n, br.Len())
+			return
+		}
+
+		b := make([]byte, n)
+		_, err = io.ReadFull(br, b)
+		v = string(b)
+		return
+	}
+
+	version, err := binary.ReadUvarint(br)
+	if err != nil {
+		return
+	}
+
+	snap = &snapshot{Version: int(version)}
+	if snap.Version < snapshotMinVersion || snap.Version > snapshotVersion {
+		return
+	}
+
+	total, err := binary.ReadUvarint(br)
+	if err != nil {
+		return
+	}
+
+	for i := uint64(0); i < total; i++ {
+		job := &snapshotJob{}
+		if job.Key, err = readString(); err != nil {
+			return
+		}
+
+		if job.Handler, err = readString(); err != nil {
+			return
+		}
+
+		if job.TimeZone, err = readString(); err != nil {
+			return
+		}
+
+		var unixNano int64
+		if unixNano, err = binary.ReadVarint(br); err != nil {
+			return
+		}
+
+		job.DateTime = time.Unix(0, unixNano)
+		if snap.Version >= 2 {
+			if err = readJobState(br, job, readString); err != nil {
+				return
+			}
+		}
+
+		if snap.Version >= 3 {
+			if job.Trigger, err = readTrigger(br, readString); err != nil {
+				return
+			}
+		}
+		snap.Jobs = append(snap.Jobs, job)
+	}
+	return
+}
+
+// readJobState reads the tags and the paused state added by version 2.
+func readJobState(br *bytes.Reader, job *snapshotJob, readString func() (string, error)) (err error) {
+	total, err := binary.ReadUvarint(br)
+	if err != nil {
+		return
+	}
+
+	if total > uint64(br.Len()) {
+		return fmt.Errorf("%d tags exceed the %d remaining bytes", total, br.Len())
+	}
+
+	for i := uint64(0); i < total; i++ {
+		var tag string
+		if tag, err = readString(); err != nil {
+			return
+		}
+		job.Tags = append(job.Tags, tag)
+	}
+
+	paused, err := br.ReadByte()
+	if err != nil || paused == 0 {
+		return
+	}
+
+	remaining, err := binary.ReadVarint(br)
+	job.Paused, job.Remaining = true, time.Duration(remaining)
+	return
+}
+
+// readTrigger reads the trigger added by version 3, nil for a job without
+// trigger.
+func readTrigger(br *bytes.Reader, readString func() (string, error)) (trigger *snapshotTrigger, err error) {
+	kind, err := readString()
+	if err != nil || kind == "" {
+		return
+	}
+
+	trigger = &snapshotTrigger{Kind: kind}
+	if trigger.Spec, err = readString(); err != nil {
+		return
+	}
+
+	if trigger.TimeZone, err = readString(); err != nil {
+		return
+	}
+
+	start, err := binary.ReadVarint(br)
+	if err != nil {
+		return
+	}
+
+	interval, err := binary.ReadVarint(br)
+	if err != nil {
+		return
+	}
+	trigger.Start, trigger.Interval = time.Unix(0, start), time.Duration(interval)
+
+	for _, dateTimes := range []*[]time.Time{&trigger.ExDates, &trigger.RDates} {
+		var total uint64
+		if total, err = binary.ReadUvarint(br); err != nil {
+			return
+		}
+
+		if total > uint64(br.Len()) {
+			return nil, fmt.Errorf("%d date times exceed the %d remaining bytes", total, br.Len())
+		}
+
+		for i := uint64(0); i < total; i++ {
+			var unixNano int64
+			if unixNano, err = binary.ReadVarint(br); err != nil {
+				return
+			}
+			*dateTimes = append(*dateTimes, time.Unix(0, unixNano))
+		}
+	}
+	return
+}
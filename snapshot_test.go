@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSnapshotScheduler(t *testing.T, handlerFn FnScheduler) *Scheduler {
+	schedule := NewScheduler(Config{TimeZone: "Asia/Jakarta"})
+	err := schedule.RegisterHandler("handler", handlerFn)
+	assert.Nil(t, err)
+	return schedule
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		for _, format := range []SnapshotFormat{SnapshotFormatJSON, SnapshotFormatBinary} {
+			format := format
+			t.Run(fmt.Sprintf("Round trip format %d", format), func(t *testing.T) {
+				t.Parallel()
+				source := newSnapshotScheduler(t, fn)
+				for i := 1; i <= 100; i++ {
+					err := source.AddHandler(fmt.Sprintf("add#%d", i), time.Duration(i)*time.Minute, "handler")
+					assert.Nil(t, err)
+				}
+
+				buf := &bytes.Buffer{}
+				err := source.Snapshot(buf, format)
+				assert.Nil(t, err)
+
+				target := newSnapshotScheduler(t, fn)
+				res, err := target.Restore(buf)
+				assert.Nil(t, err)
+				assert.Len(t, res.Restored, 100)
+				assert.Len(t, res.Conflicts, 0)
+				for i := 1; i <= 100; i++ {
+					key := fmt.Sprintf("add#%d", i)
+					_, expected := source.read(key)
+					isExists, actual := target.read(key)
+					assert.Equal(t, isExists, true)
+					assert.Equal(t, expected.handler, actual.handler)
+					assert.True(t, expected.dateTime.Equal(actual.dateTime))
+					assert.Equal(t, "Asia/Jakarta", actual.dateTime.Location().String())
+				}
+			})
+		}
+
+		t.Run("Restored job fires its handler", func(t *testing.T) {
+			t.Parallel()
+			var wg sync.WaitGroup
+			source := newSnapshotScheduler(t, fn)
+			err := source.AddHandler("add#1", 100*time.Millisecond, "handler")
+			assert.Nil(t, err)
+			buf := &bytes.Buffer{}
+			assert.Nil(t, source.Snapshot(buf))
+			assert.Nil(t, source.Cancel("add#1"))
+
+			wg.Add(1)
+			target := newSnapshotScheduler(t, func(ctx context.Context) {
+				wg.Done()
+			})
+			_, err = target.Restore(buf)
+			assert.Nil(t, err)
+			wg.Wait()
+		})
+
+		for _, format := range []SnapshotFormat{SnapshotFormatJSON, SnapshotFormatBinary} {
+			format := format
+			t.Run(fmt.Sprintf("Tags and paused state format %d", format), func(t *testing.T) {
+				t.Parallel()
+				source := newSnapshotScheduler(t, fn)
+				assert.Nil(t, source.AddHandler("add#1", time.Hour, "handler", JobOption{Tags: []string{"billing", "daily"}}))
+				assert.Nil(t, source.AddHandler("add#2", 2*time.Hour, "handler"))
+				assert.Nil(t, source.Pause("add#2"))
+				assert.Nil(t, source.Add("closure", time.Hour, fn))
+
+				buf := &bytes.Buffer{}
+				assert.Nil(t, source.Snapshot(buf, format))
+				target := newSnapshotScheduler(t, fn)
+				res, err := target.Restore(buf)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"add#1", "add#2"}, res.Restored)
+
+				info, err := target.Get("add#1")
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"billing", "daily"}, info.Tags)
+				assert.Equal(t, JobStatusPending, info.Status)
+
+				info, err = target.Get("add#2")
+				assert.Nil(t, err)
+				assert.Equal(t, JobStatusPaused, info.Status)
+				assert.Nil(t, target.Resume("add#2"))
+				_, ds := target.read("add#2")
+				assert.WithinDuration(t, time.Now().Add(2*time.Hour), ds.dateTime, time.Second)
+			})
+		}
+
+		for _, format := range []SnapshotFormat{SnapshotFormatJSON, SnapshotFormatBinary} {
+			format := format
+			t.Run(fmt.Sprintf("Triggers format %d", format), func(t *testing.T) {
+				t.Parallel()
+				jakarta, err := time.LoadLocation("Asia/Jakarta")
+				assert.Nil(t, err)
+				start := time.Date(2030, 1, 6, 8, 0, 0, 0, jakarta)
+				cron, err := ParseCron("0 9 * * mon-fri", jakarta)
+				assert.Nil(t, err)
+				rrule, err := ParseRRule(start, "FREQ=WEEKLY;BYDAY=MO;COUNT=10")
+				assert.Nil(t, err)
+				rrule.Exclude(start.AddDate(0, 0, 8))
+				rrule.Include(start.AddDate(0, 0, 3))
+
+				source := newSnapshotScheduler(t, fn)
+				triggers := map[string]Trigger{
+					"cron":     cron,
+					"interval": NewInterval(start, 90*time.Minute),
+					"rrule":    rrule,
+				}
+				for key, trigger := range triggers {
+					assert.Nil(t, source.AddTriggerHandler(key, trigger, "handler"))
+				}
+				custom := &intervalTrigger{start: time.Now().Add(time.Hour), interval: time.Hour, total: 3}
+				assert.Nil(t, source.AddTriggerHandler("custom", custom, "handler"))
+
+				buf := &bytes.Buffer{}
+				assert.Nil(t, source.Snapshot(buf, format))
+				target := newSnapshotScheduler(t, fn)
+				res, err := target.Restore(buf)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"cron", "interval", "rrule"}, res.Restored)
+
+				after := start.Add(-time.Minute)
+				for key, trigger := range triggers {
+					_, ds := target.read(key)
+					assert.IsType(t, trigger, ds.trigger)
+					assert.Equal(t, trigger.String(), ds.trigger.String())
+					assert.Equal(t, nextDateTimes(trigger, after, 5), nextDateTimes(ds.trigger, after, 5))
+				}
+			})
+		}
+
+		t.Run("Restore version 1", func(t *testing.T) {
+			t.Parallel()
+			dateTime := time.Now().Add(time.Hour)
+			buf := bytes.NewBufferString("GSS\x01\x01\x05add#1\x07handler\x03UTC")
+			buf.Write(binary.AppendVarint(nil, dateTime.UnixNano()))
+
+			target := newSnapshotScheduler(t, fn)
+			res, err := target.Restore(buf)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"add#1"}, res.Restored)
+			_, ds := target.read("add#1")
+			assert.True(t, dateTime.Equal(ds.dateTime))
+		})
+
+		t.Run("Skip and overwrite conflicts", func(t *testing.T) {
+			t.Parallel()
+			source := newSnapshotScheduler(t, fn)
+			assert.Nil(t, source.AddHandler("add#1", time.Hour, "handler"))
+			assert.Nil(t, source.AddHandler("add#2", time.Hour, "handler"))
+			buf := &bytes.Buffer{}
+			assert.Nil(t, source.Snapshot(buf))
+			data := buf.Bytes()
+
+			target := newSnapshotScheduler(t, fn)
+			assert.Nil(t, target.AddHandler("add#1", time.Minute, "handler"))
+			res, err := target.Restore(bytes.NewReader(data), RestoreOption{Strategy: RestoreStrategySkip})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"add#1"}, res.Conflicts)
+			assert.Equal(t, []string{"add#2"}, res.Restored)
+			_, ds := target.read("add#1")
+			assert.WithinDuration(t, time.Now().Add(time.Minute), ds.dateTime, time.Second)
+
+			res, err = target.Restore(bytes.NewReader(data), RestoreOption{Strategy: RestoreStrategyOverwrite})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"add#1", "add#2"}, res.Conflicts)
+			assert.Equal(t, []string{"add#1", "add#2"}, res.Restored)
+			_, ds = target.read("add#1")
+			assert.WithinDuration(t, time.Now().Add(time.Hour), ds.dateTime, time.Second)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Fail on conflicts", func(t *testing.T) {
+			t.Parallel()
+			source := newSnapshotScheduler(t, fn)
+			assert.Nil(t, source.AddHandler("add#1", time.Hour, "handler"))
+			assert.Nil(t, source.AddHandler("add#2", time.Hour, "handler"))
+			buf := &bytes.Buffer{}
+			assert.Nil(t, source.Snapshot(buf, SnapshotFormatBinary))
+
+			target := newSnapshotScheduler(t, fn)
+			assert.Nil(t, target.AddHandler("add#2", time.Minute, "handler"))
+			res, err := target.Restore(buf)
+			assert.Equal(t, ErrKeyIsExists, err)
+			assert.Equal(t, []string{"add#2"}, res.Conflicts)
+			isExists, _ := target.read("add#1")
+			assert.Equal(t, isExists, false)
+		})
+
+		t.Run("Handler is not registered", func(t *testing.T) {
+			t.Parallel()
+			source := newSnapshotScheduler(t, fn)
+			assert.Nil(t, source.AddHandler("add#1", time.Hour, "handler"))
+			buf := &bytes.Buffer{}
+			assert.Nil(t, source.Snapshot(buf))
+			data := buf.Bytes()
+
+			target := NewScheduler()
+			_, err := target.Restore(bytes.NewReader(data))
+			assert.ErrorIs(t, err, ErrHandlerIsNotExists)
+			res, err := target.Restore(bytes.NewReader(data), RestoreOption{Strategy: RestoreStrategySkip})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"add#1"}, res.Skipped)
+		})
+
+		t.Run("Invalid snapshot", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			_, err := schedule.Restore(strings.NewReader("not a snapshot"))
+			assert.ErrorIs(t, err, ErrSnapshotFormat)
+			_, err = schedule.Restore(strings.NewReader(`{"version":99,"jobs":[]}`))
+			assert.Equal(t, ErrSnapshotVersion, err)
+			_, err = schedule.Restore(strings.NewReader("GSS\x63"))
+			assert.Equal(t, ErrSnapshotVersion, err)
+			_, err = schedule.Restore(strings.NewReader("GSS\x01\x01\xff\xff\xff\xff\xff\xff\xff\xff\x7f"))
+			assert.ErrorIs(t, err, ErrSnapshotFormat)
+			_, err = schedule.Restore(strings.NewReader("GSS\x01\x01\x05add"))
+			assert.ErrorIs(t, err, ErrSnapshotFormat)
+			err = schedule.Snapshot(&bytes.Buffer{}, SnapshotFormat(99))
+			assert.Equal(t, ErrSnapshotFormat, err)
+		})
+	})
+}
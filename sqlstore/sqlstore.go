@@ -0,0 +1,128 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLeaseTable = "scheduler_leases"
+)
+
+type Placeholder int
+
+const (
+	PlaceholderQuestion Placeholder = iota
+	PlaceholderDollar
+)
+
+type Config struct {
+	LeaseTable  string
+	Placeholder Placeholder
+}
+
+type Store struct {
+	db     *sql.DB
+	config Config
+}
+
+func NewStore(db *sql.DB, configs ...Config) *Store {
+	config := Config{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.LeaseTable == "" {
+		config.LeaseTable = defaultLeaseTable
+	}
+
+	return &Store{
+		db:     db,
+		config: config,
+	}
+}
+
+func (s *Store) query(query string) string {
+	query = strings.ReplaceAll(query, "{leases}", s.config.LeaseTable)
+	if s.config.Placeholder != PlaceholderDollar {
+		return query
+	}
+
+	var (
+		sb strings.Builder
+		n  int
+	)
+
+	for _, r := range query {
+		if r != '?' {
+			sb.WriteRune(r)
+			continue
+		}
+
+		n++
+		fmt.Fprintf(&sb, "$%d", n)
+	}
+	return sb.String()
+}
+
+func (s *Store) Migrate(ctx context.Context) (err error) {
+	_, err = s.db.ExecContext(ctx, s.query(`CREATE TABLE IF NOT EXISTS {leases} (
+	name VARCHAR(255) NOT NULL PRIMARY KEY,
+	owner VARCHAR(255) NOT NULL,
+	expires_at BIGINT NOT NULL
+)`))
+	return
+}
+
+func (s *Store) Lock(ctx context.Context, name, owner string, ttl time.Duration) (isLocked bool, err error) {
+	now := time.Now()
+	res, err := s.db.ExecContext(ctx,
+		s.query(`UPDATE {leases} SET owner = ?, expires_at = ? WHERE name = ? AND (owner = ? OR expires_at <= ?)`),
+		owner, toMillis(now.Add(ttl)), name, owner, toMillis(now),
+	)
+	if err != nil {
+		return
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil || affected > 0 {
+		isLocked = err == nil
+		return
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		s.query(`INSERT INTO {leases} (name, owner, expires_at) VALUES (?, ?, ?)`),
+		name, owner, toMillis(now.Add(ttl)),
+	)
+	if err == nil {
+		isLocked = true
+		return
+	}
+
+	var exists int
+	if errExists := s.db.QueryRowContext(ctx, s.query(`SELECT 1 FROM {leases} WHERE name = ?`), name).Scan(&exists); errExists == nil {
+		err = nil
+	}
+	return
+}
+
+func (s *Store) Unlock(ctx context.Context, name, owner string) (err error) {
+	_, err = s.db.ExecContext(ctx, s.query(`DELETE FROM {leases} WHERE name = ? AND owner = ?`), name, owner)
+	return
+}
+
+func (s *Store) Purge(ctx context.Context) (n int64, err error) {
+	res, err := s.db.ExecContext(ctx, s.query(`DELETE FROM {leases} WHERE expires_at <= ?`), toMillis(time.Now()))
+	if err != nil {
+		return
+	}
+
+	return res.RowsAffected()
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
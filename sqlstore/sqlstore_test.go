@@ -0,0 +1,145 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	scheduler "github.com/sodri126/go-simple-scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStore(t *testing.T) *Store {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", url.PathEscape(t.Name())))
+	assert.Nil(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	store := NewStore(db)
+	assert.Nil(t, store.Migrate(context.Background()))
+	return store
+}
+
+func TestQuery(t *testing.T) {
+	store := NewStore(nil, Config{Placeholder: PlaceholderDollar})
+	query := store.query(`UPDATE {leases} SET owner = ? WHERE name = ?`)
+	assert.Equal(t, `UPDATE scheduler_leases SET owner = $1 WHERE name = $2`, query)
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Lock, extend and unlock", func(t *testing.T) {
+			store := newStore(t)
+			isLocked, err := store.Lock(ctx, "lease", "first", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			isLocked, err = store.Lock(ctx, "lease", "first", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			assert.Nil(t, store.Unlock(ctx, "lease", "first"))
+			isLocked, err = store.Lock(ctx, "lease", "second", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+		})
+
+		t.Run("Lock after lease expired", func(t *testing.T) {
+			store := newStore(t)
+			isLocked, err := store.Lock(ctx, "lease", "first", 50*time.Millisecond)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			time.Sleep(100 * time.Millisecond)
+			isLocked, err = store.Lock(ctx, "lease", "second", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Lock is held by another owner", func(t *testing.T) {
+			store := newStore(t)
+			isLocked, err := store.Lock(ctx, "lease", "first", time.Minute)
+			assert.Nil(t, err)
+			assert.True(t, isLocked)
+
+			isLocked, err = store.Lock(ctx, "lease", "second", time.Minute)
+			assert.Nil(t, err)
+			assert.False(t, isLocked)
+
+			assert.Nil(t, store.Unlock(ctx, "lease", "second"))
+			isLocked, err = store.Lock(ctx, "lease", "second", time.Minute)
+			assert.Nil(t, err)
+			assert.False(t, isLocked)
+		})
+	})
+}
+
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	isLocked, err := store.Lock(ctx, "expired", "first", time.Millisecond)
+	assert.Nil(t, err)
+	assert.True(t, isLocked)
+	isLocked, err = store.Lock(ctx, "held", "first", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, isLocked)
+
+	time.Sleep(10 * time.Millisecond)
+	n, err := store.Purge(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	isLocked, err = store.Lock(ctx, "held", "second", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, isLocked)
+}
+
+func TestSchedulerWithExecutionLocker(t *testing.T) {
+	var (
+		store    = newStore(t)
+		counter  int32
+		dateTime = time.Now().Add(100 * time.Millisecond)
+	)
+
+	for i := 0; i < 3; i++ {
+		schedule := scheduler.NewScheduler(scheduler.Config{ExecutionLocker: store})
+		err := schedule.AddDate("add#1", dateTime, func(ctx context.Context) {
+			atomic.AddInt32(&counter, 1)
+		})
+		assert.Nil(t, err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+}
+
+func TestLeaseElector(t *testing.T) {
+	t.Run("Fail over to the follower when the leader stops", func(t *testing.T) {
+		store := newStore(t)
+		config := scheduler.LeaseElectorConfig{TTL: 300 * time.Millisecond, RetryInterval: 20 * time.Millisecond}
+		config.Owner = "first"
+		first := scheduler.NewLeaseElector(store, config)
+		time.Sleep(50 * time.Millisecond)
+		config.Owner = "second"
+		second := scheduler.NewLeaseElector(store, config)
+		defer second.Stop()
+
+		time.Sleep(100 * time.Millisecond)
+		assert.True(t, first.IsLeader())
+		assert.False(t, second.IsLeader())
+
+		assert.Nil(t, first.Stop())
+		assert.False(t, first.IsLeader())
+		assert.Eventually(t, second.IsLeader, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHistorySize   = 10
+	defaultHistoryMaxAge = 24 * time.Hour
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+	OutcomeSkipped Outcome = "skipped"
+)
+
+type Run struct {
+	DateTime  time.Time
+	StartTime time.Time
+	EndTime   time.Time
+	Attempt   int
+	Outcome   Outcome
+	Err       error
+	Output    string
+}
+
+type Stats struct {
+	Pending int
+	Running int
+	Paused  int
+
+	Scheduled   uint64
+	Rescheduled uint64
+	Replaced    uint64
+	Cancelled   uint64
+	Started     uint64
+	Succeeded   uint64
+	Failed      uint64
+	Skipped     uint64
+	Removed     uint64
+}
+
+type runs struct {
+	items []*Run
+	next  int
+	total int
+}
+
+func (r *runs) add(run *Run) {
+	if r.total < len(r.items) {
+		r.total++
+	}
+
+	r.items[r.next] = run
+	r.next = (r.next + 1) % len(r.items)
+}
+
+func (r *runs) list(after time.Time) (res []*Run) {
+	for i := r.total; i > 0; i-- {
+		run := r.items[(r.next-i+len(r.items))%len(r.items)]
+		if run.recordTime().After(after) {
+			res = append(res, run)
+		}
+	}
+	return
+}
+
+func (r *Run) recordTime() time.Time {
+	if r.EndTime.IsZero() {
+		return r.DateTime
+	}
+
+	return r.EndTime
+}
+
+type statsListener struct {
+	mutex     sync.Mutex
+	stats     Stats
+	histories map[string]*runs
+	size      int
+	maxAge    time.Duration
+	lastSweep time.Time
+}
+
+func newStatsListener(size int, maxAge time.Duration) *statsListener {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+
+	if maxAge <= 0 {
+		maxAge = defaultHistoryMaxAge
+	}
+
+	return &statsListener{
+		histories: make(map[string]*runs),
+		size:      size,
+		maxAge:    maxAge,
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *statsListener) count(counter *uint64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	*counter++
+}
+
+func (l *statsListener) record(counter *uint64, event Event, outcome Outcome) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	*counter++
+	r, isExists := l.histories[event.Key]
+	if !isExists {
+		r = &runs{items: make([]*Run, l.size)}
+		l.histories[event.Key] = r
+	}
+
+	r.add(&Run{
+		DateTime:  event.DateTime,
+		StartTime: event.StartTime,
+		EndTime:   event.EndTime,
+		Attempt:   event.Attempt,
+		Outcome:   outcome,
+		Err:       event.Err,
+		Output:    event.Output,
+	})
+
+	if time.Since(l.lastSweep) > l.maxAge/10 {
+		l.sweep()
+	}
+}
+
+func (l *statsListener) sweep() {
+	after := time.Now().Add(-l.maxAge)
+	for key, r := range l.histories {
+		if len(r.list(after)) == 0 {
+			delete(l.histories, key)
+		}
+	}
+	l.lastSweep = time.Now()
+}
+
+func (l *statsListener) history(key string) []*Run {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	r, isExists := l.histories[key]
+	if !isExists {
+		return nil
+	}
+
+	return r.list(time.Now().Add(-l.maxAge))
+}
+
+func (l *statsListener) keys() (res []string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for key := range l.histories {
+		res = append(res, key)
+	}
+
+	sort.Strings(res)
+	return
+}
+
+func (l *statsListener) OnScheduled(event Event)   { l.count(&l.stats.Scheduled) }
+func (l *statsListener) OnRescheduled(event Event) { l.count(&l.stats.Rescheduled) }
+func (l *statsListener) OnReplaced(event Event)    { l.count(&l.stats.Replaced) }
+func (l *statsListener) OnCancelled(event Event)   { l.count(&l.stats.Cancelled) }
+func (l *statsListener) OnStart(event Event)       { l.count(&l.stats.Started) }
+func (l *statsListener) OnRemoved(event Event)     { l.count(&l.stats.Removed) }
+
+func (l *statsListener) OnSuccess(event Event) {
+	l.record(&l.stats.Succeeded, event, OutcomeSuccess)
+}
+
+func (l *statsListener) OnFailure(event Event) {
+	l.record(&l.stats.Failed, event, OutcomeFailure)
+}
+
+func (l *statsListener) OnSkipped(event Event) {
+	l.record(&l.stats.Skipped, event, OutcomeSkipped)
+}
+
+func (s *Scheduler) Stats() Stats {
+	s.stats.mutex.Lock()
+	stats := s.stats.stats
+	s.stats.mutex.Unlock()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats.Paused = s.paused()
+	stats.Pending = len(s.schedulers) - stats.Paused
+	stats.Running = s.running
+	return stats
+}
+
+// History returns the retained runs of key, oldest first.
+func (s *Scheduler) History(key string) []*Run {
+	return s.stats.history(key)
+}
+
+// HistoryKeys returns the keys having retained runs, sorted.
+func (s *Scheduler) HistoryKeys() []string {
+	return s.stats.keys()
+}
+
+func (s *Scheduler) toResponseHistory() (res []*ResponseScheduler) {
+	for _, key := range s.stats.keys() {
+		history := s.stats.history(key)
+		if len(history) == 0 {
+			continue
+		}
+
+		res = append(res, &ResponseScheduler{
+			Key:     key,
+			Time:    history[len(history)-1].DateTime,
+			History: history,
+		})
+	}
+	return
+}
+
+func (s *Scheduler) ListHistory(w io.Writer, lcs ...ListConverter) (n int, err error) {
+	lc := NewHistoryResponse()
+	if len(lcs) > 0 {
+		lc = lcs[0]
+	}
+
+	return writeList(w, iterateResponse(s.toResponseHistory()), lc)
+}
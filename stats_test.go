@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Count scheduler wide events", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, fn))
+			assert.Nil(t, schedule.Add("add#2", 10*time.Millisecond, func(ctx context.Context) {
+				Fail(ctx, errors.New("failure"))
+			}))
+			assert.Nil(t, schedule.Add("add#3", time.Hour, fn))
+			assert.Nil(t, schedule.Add("add#4", time.Hour, fn))
+			assert.Nil(t, schedule.Add("add#5", time.Hour, fn))
+			assert.Nil(t, schedule.Reschedule("add#3", 2*time.Hour))
+			assert.Nil(t, schedule.Replace("add#4", 2*time.Hour, fn))
+			assert.Nil(t, schedule.Pause("add#4"))
+			assert.Nil(t, schedule.Cancel("add#5"))
+			time.Sleep(50 * time.Millisecond)
+
+			assert.Equal(t, Stats{
+				Pending:     1,
+				Paused:      1,
+				Scheduled:   5,
+				Rescheduled: 1,
+				Replaced:    1,
+				Cancelled:   1,
+				Started:     2,
+				Succeeded:   1,
+				Failed:      1,
+				Removed:     2,
+			}, schedule.Stats())
+		})
+
+		t.Run("History keeps the latest runs", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler(Config{HistorySize: 3})
+			failure := errors.New("failure")
+			for i := 1; i <= 5; i++ {
+				index := i
+				assert.Nil(t, schedule.Add("add#1", time.Millisecond, func(ctx context.Context) {
+					if index%2 == 0 {
+						Fail(ctx, failure)
+					}
+				}))
+				assert.Eventually(t, func() bool {
+					isExists, _ := schedule.read("add#1")
+					return !isExists
+				}, time.Second, time.Millisecond)
+			}
+
+			history := schedule.History("add#1")
+			assert.Len(t, history, 3)
+			assert.Equal(t, OutcomeSuccess, history[0].Outcome)
+			assert.Equal(t, OutcomeFailure, history[1].Outcome)
+			assert.Equal(t, failure, history[1].Err)
+			assert.Equal(t, OutcomeSuccess, history[2].Outcome)
+			assert.True(t, history[0].StartTime.Before(history[2].StartTime))
+			assert.False(t, history[2].EndTime.Before(history[2].StartTime))
+			assert.Equal(t, 1, history[2].Attempt)
+		})
+
+		t.Run("History expires by age", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler(Config{HistoryMaxAge: 50 * time.Millisecond})
+			assert.Nil(t, schedule.Add("add#1", time.Millisecond, fn))
+			time.Sleep(20 * time.Millisecond)
+			assert.Len(t, schedule.History("add#1"), 1)
+			time.Sleep(50 * time.Millisecond)
+			assert.Len(t, schedule.History("add#1"), 0)
+
+			assert.Nil(t, schedule.Add("add#2", time.Millisecond, fn))
+			time.Sleep(20 * time.Millisecond)
+			schedule.stats.mutex.Lock()
+			assert.Len(t, schedule.stats.histories, 1)
+			schedule.stats.mutex.Unlock()
+		})
+
+		t.Run("List history", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			for i := 1; i <= 3; i++ {
+				assert.Nil(t, schedule.Add(fmt.Sprintf("add#%d", i), time.Millisecond, fn))
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			buf := &bytes.Buffer{}
+			n, err := schedule.ListHistory(buf)
+			assert.Nil(t, err)
+			assert.Greater(t, n, 0)
+			for i := 1; i <= 3; i++ {
+				assert.Contains(t, buf.String(), fmt.Sprintf("add#%d", i))
+			}
+			assert.Contains(t, buf.String(), string(OutcomeSuccess))
+			assert.Equal(t, []string{"add#1", "add#2", "add#3"}, schedule.HistoryKeys())
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("History of unknown key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.History("add#1"))
+		})
+	})
+}
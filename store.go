@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type StoreJob struct {
+	Key      string
+	Handler  string
+	DateTime time.Time
+	Attempt  int
+}
+
+func (job *StoreJob) toEvent() Event {
+	event := Event{
+		Key:      job.Key,
+		Handler:  job.Handler,
+		DateTime: job.DateTime,
+		Attempt:  job.Attempt,
+	}
+
+	if event.Attempt == 0 {
+		event.Attempt = 1
+	}
+	return event
+}
+
+// Store keeps jobs outside of the process so several schedulers can share
+// them. Add returns ErrKeyIsExists for a known key, Reschedule and Cancel
+// return ErrKeyIsNotExists for an unknown one. Claim atomically removes and
+// returns at most limit jobs due at now, so a job is only claimed once.
+type Store interface {
+	Add(ctx context.Context, job *StoreJob) error
+	Reschedule(ctx context.Context, key string, dateTime time.Time) error
+	Cancel(ctx context.Context, key string) error
+	Claim(ctx context.Context, now time.Time, limit int) ([]*StoreJob, error)
+}
+
+// LeaseStore lets several workers share the due jobs of a Store. Lease hands
+// out due jobs to owner until ttl elapses, after that they are due again so a
+// dead worker never loses them. Ack removes a leased job and Release makes it
+// due again at dateTime, both return ErrLeaseIsNotHeld when owner lost it.
+type LeaseStore interface {
+	Store
+	Lease(ctx context.Context, owner string, now time.Time, ttl time.Duration, limit int) ([]*StoreJob, error)
+	Ack(ctx context.Context, key, owner string) error
+	Release(ctx context.Context, key, owner string, dateTime time.Time) error
+}
+
+func (s *Scheduler) poll() {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.claim()
+		}
+	}
+}
+
+func (s *Scheduler) claim() {
+	if !s.isLeader() {
+		return
+	}
+
+	if leaseStore, isLeaseStore := s.config.Store.(LeaseStore); isLeaseStore && s.config.LeaseTTL > 0 {
+		s.lease(leaseStore)
+		return
+	}
+
+	jobs, err := s.config.Store.Claim(context.Background(), time.Now(), s.config.ClaimLimit)
+	if err != nil {
+		s.log(slog.LevelError, "claim failed", slog.Any("error", err))
+		return
+	}
+
+	for i := 0; i < len(jobs); i++ {
+		fn, err := s.handler(jobs[i].Handler)
+		if err != nil {
+			s.unclaim(jobs[i], err)
+			continue
+		}
+
+		go s.executeRetrying(jobs[i].toEvent(), fn)
+	}
+}
+
+// unclaim puts back a job claimed without its handler, like during a
+// rolling deploy, so a scheduler registering the handler claims it later.
+func (s *Scheduler) unclaim(job *StoreJob, cause error) {
+	attrs := []any{slog.String("key", job.Key), slog.String("handler", job.Handler), slog.Any("error", cause)}
+	if err := s.config.Store.Add(context.Background(), job); err != nil {
+		s.log(slog.LevelError, "job dropped", append(attrs, slog.Any("add_error", err))...)
+		return
+	}
+
+	s.log(slog.LevelWarn, "job returned to the store", attrs...)
+}
+
+func (s *Scheduler) lease(store LeaseStore) {
+	s.mutex.RLock()
+	limit := s.config.ClaimLimit - s.leased
+	s.mutex.RUnlock()
+
+	if limit <= 0 {
+		return
+	}
+
+	jobs, err := store.Lease(context.Background(), s.owner, time.Now(), s.config.LeaseTTL, limit)
+	if err != nil {
+		s.log(slog.LevelError, "lease failed", slog.Any("error", err))
+		return
+	}
+
+	s.mutex.Lock()
+	s.leased += len(jobs)
+	s.mutex.Unlock()
+
+	for i := 0; i < len(jobs); i++ {
+		go s.executeLease(store, jobs[i])
+	}
+}
+
+func (s *Scheduler) executeLease(store LeaseStore, job *StoreJob) {
+	defer func() {
+		s.mutex.Lock()
+		s.leased--
+		s.mutex.Unlock()
+	}()
+
+	fn, err := s.handler(job.Handler)
+	if err == nil {
+		_, err = s.execute(job.toEvent(), fn)
+	}
+
+	ctx := context.Background()
+	if err != nil {
+		if err = store.Release(ctx, job.Key, s.owner, time.Now().Add(s.config.LeaseRetryDelay)); err != nil {
+			s.log(slog.LevelWarn, "release failed", slog.String("key", job.Key), slog.Any("error", err))
+		}
+		return
+	}
+
+	if err = store.Ack(ctx, job.Key, s.owner); err != nil {
+		s.log(slog.LevelWarn, "ack failed", slog.String("key", job.Key), slog.Any("error", err))
+	}
+}
+
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.log(slog.LevelInfo, "scheduler stopped", slog.Int("pending", s.Pending()), slog.Int("running", s.Running()))
+	})
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWorkers(t *testing.T, store Store, total, claimLimit int, fn func(index int) FnScheduler) []*Scheduler {
+	workers := make([]*Scheduler, total)
+	for i := range workers {
+		workers[i] = NewScheduler(Config{
+			Store:        store,
+			PollInterval: 5 * time.Millisecond,
+			ClaimLimit:   claimLimit,
+			LeaseTTL:     time.Second,
+		})
+		t.Cleanup(workers[i].Stop)
+		assert.Nil(t, workers[i].RegisterHandler("handler", fn(i)))
+	}
+	return workers
+}
+
+func TestMemoryStoreLease(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Lease, release and ack", func(t *testing.T) {
+			t.Parallel()
+			store := NewMemoryStore()
+			now := time.Now()
+			assert.Nil(t, store.Add(ctx, &StoreJob{Key: "add#1", Handler: "handler", DateTime: now}))
+
+			jobs, err := store.Lease(ctx, "first", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			jobs, err = store.Lease(ctx, "second", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 0)
+
+			assert.Nil(t, store.Release(ctx, "add#1", "first", now))
+			jobs, err = store.Lease(ctx, "second", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			assert.True(t, now.Equal(jobs[0].DateTime))
+			assert.Equal(t, 2, jobs[0].Attempt)
+
+			assert.Nil(t, store.Ack(ctx, "add#1", "second"))
+			assert.Equal(t, ErrKeyIsNotExists, store.Cancel(ctx, "add#1"))
+		})
+
+		t.Run("Reclaim expired lease", func(t *testing.T) {
+			t.Parallel()
+			store := NewMemoryStore()
+			now := time.Now()
+			assert.Nil(t, store.Add(ctx, &StoreJob{Key: "add#1", DateTime: now}))
+
+			jobs, err := store.Lease(ctx, "first", now, time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			jobs, err = store.Lease(ctx, "second", now.Add(2*time.Minute), time.Minute, 10)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+			assert.Equal(t, ErrLeaseIsNotHeld, store.Ack(ctx, "add#1", "first"))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Ack and release without lease", func(t *testing.T) {
+			t.Parallel()
+			store := NewMemoryStore()
+			assert.Nil(t, store.Add(ctx, &StoreJob{Key: "add#1", DateTime: time.Now()}))
+			assert.Equal(t, ErrLeaseIsNotHeld, store.Ack(ctx, "add#1", "first"))
+			assert.Equal(t, ErrLeaseIsNotHeld, store.Release(ctx, "add#1", "first", time.Now()))
+			assert.Equal(t, ErrLeaseIsNotHeld, store.Ack(ctx, "add#2", "first"))
+		})
+	})
+}
+
+func TestWorker(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Every job runs once and workers share them", func(t *testing.T) {
+			t.Parallel()
+			var (
+				wg     sync.WaitGroup
+				store  = NewMemoryStore()
+				mutex  sync.Mutex
+				owners = make(map[int]int)
+			)
+
+			wg.Add(500)
+			workers := newWorkers(t, store, 4, 5, func(index int) FnScheduler {
+				return func(ctx context.Context) {
+					mutex.Lock()
+					owners[index]++
+					mutex.Unlock()
+					time.Sleep(time.Millisecond)
+					wg.Done()
+				}
+			})
+
+			for i := 1; i <= 500; i++ {
+				assert.Nil(t, workers[i%len(workers)].AddHandler(fmt.Sprintf("add#%d", i), 0, "handler"))
+			}
+
+			start := time.Now()
+			wg.Wait()
+			assert.Less(t, time.Since(start), 5*time.Second)
+			assert.Len(t, owners, len(workers))
+			for _, total := range owners {
+				assert.Greater(t, total, 50)
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			jobs, err := store.Claim(context.Background(), time.Now().Add(time.Hour), 1000)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 0)
+		})
+
+		t.Run("Failed job is released and retried", func(t *testing.T) {
+			t.Parallel()
+			var (
+				attempts int32
+				done     = make(chan struct{})
+				store    = NewMemoryStore()
+			)
+
+			workers := newWorkers(t, store, 2, 1, func(index int) FnScheduler {
+				return func(ctx context.Context) {
+					switch atomic.AddInt32(&attempts, 1) {
+					case 1:
+						Fail(ctx, errors.New("failed"))
+					case 2:
+						panic("failed")
+					default:
+						close(done)
+					}
+				}
+			})
+			assert.Nil(t, workers[0].AddHandler("add#1", 0, "handler"))
+
+			<-done
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+		})
+
+		t.Run("Job claimed without handler is put back", func(t *testing.T) {
+			t.Parallel()
+			var (
+				runs  int32
+				store = NewMemoryStore()
+				ctx   = context.Background()
+			)
+
+			newReplica := func() *Scheduler {
+				replica := NewScheduler(Config{Store: store, PollInterval: 5 * time.Millisecond})
+				t.Cleanup(replica.Stop)
+				return replica
+			}
+
+			newReplica()
+			dateTime := time.Now()
+			assert.Nil(t, store.Add(ctx, &StoreJob{Key: "add#1", Handler: "handler", DateTime: dateTime}))
+			time.Sleep(50 * time.Millisecond)
+
+			assert.Nil(t, newReplica().RegisterHandler("handler", func(ctx context.Context) {
+				exec, _ := ExecutionFromContext(ctx)
+				assert.True(t, dateTime.Equal(exec.DateTime))
+				atomic.AddInt32(&runs, 1)
+			}))
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&runs) == 1
+			}, 5*time.Second, 5*time.Millisecond)
+		})
+
+		t.Run("Job of a dead worker is reclaimed", func(t *testing.T) {
+			t.Parallel()
+			var (
+				done  = make(chan struct{})
+				store = NewMemoryStore()
+				ctx   = context.Background()
+			)
+
+			assert.Nil(t, store.Add(ctx, &StoreJob{Key: "add#1", Handler: "handler", DateTime: time.Now()}))
+			jobs, err := store.Lease(ctx, "dead", time.Now(), 100*time.Millisecond, 1)
+			assert.Nil(t, err)
+			assert.Len(t, jobs, 1)
+
+			start := time.Now()
+			newWorkers(t, store, 1, 1, func(index int) FnScheduler {
+				return func(ctx context.Context) {
+					close(done)
+				}
+			})
+			<-done
+			assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		})
+	})
+}
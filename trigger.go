@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// Trigger computes the date times of a recurring job. Next returns the
+// first date time strictly after after, ok is false once the trigger is
+// exhausted. String describes the trigger.
+type Trigger interface {
+	Next(after time.Time) (next time.Time, ok bool)
+	String() string
+}
+
+func (s *Scheduler) triggerParam(trigger Trigger, opts []JobOption) (param *paramScheduler, err error) {
+	next, ok := trigger.Next(time.Now())
+	if !ok {
+		err = ErrTriggerIsExhausted
+		return
+	}
+
+	param = &paramScheduler{
+		duration: time.Until(next),
+		dateTime: next,
+		tags:     jobTags(opts),
+		trigger:  trigger,
+	}
+	return
+}
+
+// rearm schedules the next date time of a recurring job fired at dateTime,
+// it returns false when the job is done. A job rescheduled, replaced or
+// cancelled while running is left as it is.
+func (s *Scheduler) rearm(ds *detailScheduler, dateTime time.Time) bool {
+	s.mutex.Lock()
+	if s.schedulers[ds.key] != ds || !ds.dateTime.Equal(dateTime) {
+		s.mutex.Unlock()
+		return true
+	}
+
+	after := time.Now()
+	if dateTime.After(after) {
+		after = dateTime
+	}
+
+	next, ok := ds.trigger.Next(after)
+	if !ok {
+		s.mutex.Unlock()
+		return false
+	}
+
+	event := ds.toEvent()
+	event.PreviousDateTime, event.DateTime = ds.dateTime, next
+	s.schedulersIndex.Move(ds, next)
+	if ds.pauseOnRearm {
+		ds.pauseOnRearm = false
+		ds.remaining = time.Until(next)
+	} else {
+		ds.timer.Reset(time.Until(next))
+	}
+	s.mutex.Unlock()
+
+	s.emit(EventTypeRescheduled, event)
+	return true
+}
+
+// AddTrigger adds a job running fn at every date time of trigger. Recurring
+// jobs are kept in memory even when a Store is configured.
+func (s *Scheduler) AddTrigger(key string, trigger Trigger, fn FnScheduler, opts ...JobOption) (err error) {
+	param, err := s.triggerParam(trigger, opts)
+	if err != nil {
+		return
+	}
+
+	return s.add(key, param, fn)
+}
+
+func (s *Scheduler) AddTriggerHandler(key string, trigger Trigger, handler string, opts ...JobOption) (err error) {
+	fn, err := s.handler(handler)
+	if err != nil {
+		return
+	}
+
+	param, err := s.triggerParam(trigger, opts)
+	if err != nil {
+		return
+	}
+
+	param.handler = handler
+	return s.add(key, param, fn)
+}
+
+// ReplaceTrigger replaces the job of key by a job running fn at every date
+// time of trigger, the tags of the job are kept when opts has none.
+func (s *Scheduler) ReplaceTrigger(key string, trigger Trigger, fn FnScheduler, opts ...JobOption) (err error) {
+	param, err := s.triggerParam(trigger, opts)
+	if err != nil {
+		return
+	}
+
+	return s.replace(key, param, fn)
+}
+
+// Interval is a Trigger running every interval from start.
+type Interval struct {
+	start    time.Time
+	interval time.Duration
+}
+
+func NewInterval(start time.Time, interval time.Duration) *Interval {
+	return &Interval{start: start, interval: interval}
+}
+
+func (i *Interval) Next(after time.Time) (time.Time, bool) {
+	if i.interval <= 0 {
+		return time.Time{}, false
+	}
+
+	if after.Before(i.start) {
+		return i.start, true
+	}
+
+	periods := after.Sub(i.start)/i.interval + 1
+	return i.start.Add(periods * i.interval), true
+}
+
+func (i *Interval) String() string {
+	return "every " + i.interval.String()
+}
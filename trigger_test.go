@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type intervalTrigger struct {
+	start    time.Time
+	interval time.Duration
+	total    int
+}
+
+func (t *intervalTrigger) Next(after time.Time) (time.Time, bool) {
+	for i := 0; i < t.total; i++ {
+		if next := t.start.Add(time.Duration(i) * t.interval); next.After(after) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (t *intervalTrigger) String() string {
+	return fmt.Sprintf("every %s", t.interval)
+}
+
+func TestTriggerScheduler(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Run at every date time", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			listener := newRecordListener()
+			schedule := NewScheduler(Config{Listeners: []Listener{listener}})
+			trigger := &intervalTrigger{start: time.Now().Add(20 * time.Millisecond), interval: 30 * time.Millisecond, total: 3}
+			assert.Nil(t, schedule.AddTrigger("add#1", trigger, func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			}))
+
+			info, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, "every 30ms", info.Trigger)
+			assert.Equal(t, trigger, info.Recurrence)
+
+			assert.Eventually(t, func() bool {
+				isExists, _ := schedule.read("add#1")
+				return !isExists
+			}, time.Second, 5*time.Millisecond)
+			assert.Equal(t, int32(3), atomic.LoadInt32(&counter))
+			assert.Len(t, listener.get("rescheduled"), 2)
+			assert.Len(t, listener.get("removed"), 1)
+		})
+
+		t.Run("Cancel recurring key", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.RegisterHandler("handler", func(ctx context.Context) {
+				atomic.AddInt32(&counter, 1)
+			}))
+			trigger := &intervalTrigger{start: time.Now().Add(10 * time.Millisecond), interval: 20 * time.Millisecond, total: 100}
+			assert.Nil(t, schedule.AddTriggerHandler("add#1", trigger, "handler"))
+
+			assert.Eventually(t, func() bool {
+				info, err := schedule.Get("add#1")
+				return err == nil && info.RunCount >= 2
+			}, time.Second, 5*time.Millisecond)
+			assert.Nil(t, schedule.Cancel("add#1"))
+			total := atomic.LoadInt32(&counter)
+			time.Sleep(60 * time.Millisecond)
+			assert.LessOrEqual(t, atomic.LoadInt32(&counter), total+1)
+		})
+
+		t.Run("Replace by another trigger", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Nil(t, schedule.AddTrigger("add#1", NewInterval(time.Now(), time.Hour), fn, JobOption{Tags: []string{"tag"}}))
+			cron, err := ParseCron("0 0 1 1 *", nil)
+			assert.Nil(t, err)
+			assert.Nil(t, schedule.ReplaceTrigger("add#1", cron, fn))
+
+			info, err := schedule.Get("add#1")
+			assert.Nil(t, err)
+			assert.Equal(t, "0 0 1 1 *", info.Trigger)
+			assert.Equal(t, []string{"tag"}, info.Tags)
+			assert.Equal(t, time.January, info.DateTime.Month())
+		})
+
+		t.Run("Interval", func(t *testing.T) {
+			t.Parallel()
+			start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+			interval := NewInterval(start, 90*time.Minute)
+			assert.Equal(t, "every 1h30m0s", interval.String())
+			assert.Equal(t, []string{"2030-01-01T00:00:00Z", "2030-01-01T01:30:00Z"}, nextDateTimes(interval, start.Add(-time.Second), 2))
+			assert.Equal(t, []string{"2030-01-01T03:00:00Z"}, nextDateTimes(interval, start.Add(90*time.Minute), 1))
+			assert.Empty(t, nextDateTimes(NewInterval(start, 0), start, 1))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Replace unknown key", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			assert.Equal(t, ErrKeyIsNotExists, schedule.ReplaceTrigger("add#1", NewInterval(time.Now(), time.Hour), fn))
+		})
+
+		t.Run("Exhausted trigger or unknown handler", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			trigger := &intervalTrigger{start: time.Now().Add(-time.Hour), interval: time.Minute, total: 3}
+			assert.Equal(t, ErrTriggerIsExhausted, schedule.AddTrigger("add#1", trigger, fn))
+			assert.Equal(t, ErrHandlerIsNotExists, schedule.AddTriggerHandler("add#1", trigger, "handler"))
+		})
+	})
+}
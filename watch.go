@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultWatchBufferSize = 64
+)
+
+// WatchFilter narrows the events sent to a watcher, an empty Types sends
+// every type of event.
+type WatchFilter struct {
+	KeyPrefix  string
+	Types      []EventType
+	BufferSize int
+}
+
+type watcher struct {
+	filter WatchFilter
+	ch     chan Event
+}
+
+func (w *watcher) match(event Event) bool {
+	if !strings.HasPrefix(event.Key, w.filter.KeyPrefix) {
+		return false
+	}
+
+	if len(w.filter.Types) == 0 {
+		return true
+	}
+
+	for _, eventType := range w.filter.Types {
+		if eventType == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+type watchHub struct {
+	NopListener
+
+	watchers map[*watcher]struct{}
+	mutex    sync.Mutex
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{
+		watchers: make(map[*watcher]struct{}),
+	}
+}
+
+func (h *watchHub) publish(event Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for w := range h.watchers {
+		if !w.match(event) {
+			continue
+		}
+
+		select {
+		case w.ch <- event:
+		default:
+			h.remove(w)
+		}
+	}
+}
+
+func (h *watchHub) remove(w *watcher) {
+	if _, isExists := h.watchers[w]; !isExists {
+		return
+	}
+
+	delete(h.watchers, w)
+	close(w.ch)
+}
+
+func (h *watchHub) OnScheduled(event Event)   { h.publish(event) }
+func (h *watchHub) OnRescheduled(event Event) { h.publish(event) }
+func (h *watchHub) OnReplaced(event Event)    { h.publish(event) }
+func (h *watchHub) OnCancelled(event Event)   { h.publish(event) }
+func (h *watchHub) OnStart(event Event)       { h.publish(event) }
+func (h *watchHub) OnSuccess(event Event)     { h.publish(event) }
+func (h *watchHub) OnFailure(event Event)     { h.publish(event) }
+func (h *watchHub) OnSkipped(event Event)     { h.publish(event) }
+func (h *watchHub) OnRemoved(event Event)     { h.publish(event) }
+
+// Watch streams the events matching filter until ctx is done. A watcher
+// whose buffer is full is dropped by closing its channel, so the scheduler
+// never waits for a slow consumer.
+func (s *Scheduler) Watch(ctx context.Context, filters ...WatchFilter) <-chan Event {
+	filter := WatchFilter{}
+	if len(filters) > 0 {
+		filter = filters[0]
+	}
+
+	if filter.BufferSize <= 0 {
+		filter.BufferSize = defaultWatchBufferSize
+	}
+
+	w := &watcher{
+		filter: filter,
+		ch:     make(chan Event, filter.BufferSize),
+	}
+
+	s.watchHub.mutex.Lock()
+	s.watchHub.watchers[w] = struct{}{}
+	s.watchHub.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.watchHub.mutex.Lock()
+		defer s.watchHub.mutex.Unlock()
+
+		s.watchHub.remove(w)
+	}()
+
+	return w.ch
+}
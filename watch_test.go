@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-ch:
+		assert.True(t, ok)
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	return Event{}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Events of a job", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := schedule.Watch(ctx)
+			assert.Nil(t, schedule.Add("watch#1", time.Hour, fn))
+			assert.Nil(t, schedule.Reschedule("watch#1", 50*time.Millisecond))
+
+			assert.Equal(t, EventTypeScheduled, receive(t, events).Type)
+			rescheduled := receive(t, events)
+			assert.Equal(t, EventTypeRescheduled, rescheduled.Type)
+			assert.Equal(t, "rescheduled", rescheduled.Type.String())
+			assert.Equal(t, "watch#1", rescheduled.Key)
+			assert.Equal(t, EventTypeStarted, receive(t, events).Type)
+			assert.Equal(t, EventTypeSucceeded, receive(t, events).Type)
+			assert.Equal(t, EventTypeRemoved, receive(t, events).Type)
+		})
+
+		t.Run("Filter by key prefix and type", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := schedule.Watch(ctx, WatchFilter{
+				KeyPrefix: "billing:",
+				Types:     []EventType{EventTypeCancelled},
+			})
+			assert.Nil(t, schedule.Add("report:1", time.Hour, fn))
+			assert.Nil(t, schedule.Add("billing:1", time.Hour, fn))
+			assert.Nil(t, schedule.Cancel("report:1"))
+			assert.Nil(t, schedule.Cancel("billing:1"))
+
+			cancelled := receive(t, events)
+			assert.Equal(t, EventTypeCancelled, cancelled.Type)
+			assert.Equal(t, "billing:1", cancelled.Key)
+			assert.Len(t, events, 0)
+		})
+
+		t.Run("Close on context done", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			ctx, cancel := context.WithCancel(context.Background())
+
+			events := schedule.Watch(ctx)
+			cancel()
+
+			select {
+			case _, ok := <-events:
+				assert.False(t, ok)
+			case <-time.After(time.Second):
+				t.Fatal("channel is not closed")
+			}
+			assert.Nil(t, schedule.Add("watch#1", time.Hour, fn))
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Drop slow consumer", func(t *testing.T) {
+			t.Parallel()
+			schedule := NewScheduler()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := schedule.Watch(ctx, WatchFilter{BufferSize: 1})
+			assert.Nil(t, schedule.Add("watch#1", time.Hour, fn))
+			assert.Nil(t, schedule.Add("watch#2", time.Hour, fn))
+			assert.Nil(t, schedule.Add("watch#3", time.Hour, fn))
+
+			assert.Equal(t, "watch#1", receive(t, events).Key)
+			_, ok := <-events
+			assert.False(t, ok)
+		})
+	})
+}
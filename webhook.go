@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+const (
+	defaultWebhookTimeout = 30 * time.Second
+
+	HeaderWebhookSignature = "X-Scheduler-Signature-256"
+)
+
+var webhookFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// StatusRange is an inclusive range of HTTP status codes.
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+// WebhookJob sends an HTTP request, its Run method is the function of a
+// job. Body is a text/template executed with the Execution of the job, the
+// json function encodes a value as JSON. A response whose status is out of
+// SuccessStatuses, default to 200-299, fails the run with ErrWebhookStatus,
+// the failure is retried by Config.RetryPolicy. The status and the body of
+// the response, at most MaxOutputBytes, are recorded in the run history.
+type WebhookJob struct {
+	// Method defaults to POST.
+	Method string
+	URL    string
+	Header http.Header
+	Body   string
+	// Timeout bounds the request, default to 30 seconds.
+	Timeout         time.Duration
+	SuccessStatuses []StatusRange
+	// Secret signs the body, the hex HMAC-SHA256 is sent prefixed by
+	// "sha256=" in the X-Scheduler-Signature-256 header.
+	Secret         []byte
+	MaxOutputBytes int
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Sign returns the value of the signature header of body.
+func (job *WebhookJob) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, job.Secret)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (job *WebhookJob) isSuccess(code int) bool {
+	if len(job.SuccessStatuses) == 0 {
+		return code >= 200 && code <= 299
+	}
+
+	for _, status := range job.SuccessStatuses {
+		if code >= status.Min && code <= status.Max {
+			return true
+		}
+	}
+	return false
+}
+
+func (job *WebhookJob) newRequest(ctx context.Context) (req *http.Request, err error) {
+	exec, _ := ExecutionFromContext(ctx)
+	tmpl, err := template.New("body").Funcs(webhookFuncs).Parse(job.Body)
+	if err != nil {
+		return
+	}
+
+	var body bytes.Buffer
+	if err = tmpl.Execute(&body, exec); err != nil {
+		return
+	}
+
+	method := job.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, method, job.URL, bytes.NewReader(body.Bytes())); err != nil {
+		return
+	}
+
+	for name, values := range job.Header {
+		req.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+
+	if job.Secret != nil {
+		req.Header.Set(HeaderWebhookSignature, job.Sign(body.Bytes()))
+	}
+	return
+}
+
+func (job *WebhookJob) Run(ctx context.Context) {
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := job.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := job.newRequest(ctx)
+	if err != nil {
+		Fail(ctx, fmt.Errorf("%w: %v", ErrWebhookFailed, err))
+		return
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		Fail(ctx, fmt.Errorf("%w: %v", ErrWebhookFailed, err))
+		return
+	}
+	defer res.Body.Close()
+
+	limit := job.MaxOutputBytes
+	if limit <= 0 {
+		limit = defaultMaxOutputBytes
+	}
+
+	output := &limitedBuffer{limit: limit}
+	fmt.Fprintf(output, "%s\n", res.Status)
+	_, err = io.Copy(output, res.Body)
+	SetOutput(ctx, output.String())
+
+	switch {
+	case !job.isSuccess(res.StatusCode):
+		Fail(ctx, fmt.Errorf("%w: %s", ErrWebhookStatus, res.Status))
+	case err != nil:
+		Fail(ctx, fmt.Errorf("%w: %v", ErrWebhookFailed, err))
+	}
+}
@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runWebhook runs job once with schedule and returns the runs of the job.
+func runWebhook(t *testing.T, schedule *Scheduler, job *WebhookJob, total int) []*Run {
+	assert.Nil(t, schedule.Add("add#1", 10*time.Millisecond, job.Run))
+	assert.Eventually(t, func() bool {
+		return len(schedule.History("add#1")) == total
+	}, 5*time.Second, 10*time.Millisecond)
+	return schedule.History("add#1")
+}
+
+func TestWebhookJob(t *testing.T) {
+	t.Run("Positive Case", func(t *testing.T) {
+		t.Run("Signed request", func(t *testing.T) {
+			t.Parallel()
+			job := &WebhookJob{
+				Method: http.MethodPut,
+				Header: http.Header{"X-Tenant": {"acme"}},
+				Body:   `{"key":{{json .Key}},"attempt":{{.Attempt}}}`,
+				Secret: []byte("secret"),
+			}
+			requests := make(chan *http.Request, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, `{"key":"add#1","attempt":1}`, string(body))
+				assert.Equal(t, job.Sign(body), r.Header.Get(HeaderWebhookSignature))
+				requests <- r
+				_, _ = io.WriteString(w, "ok")
+			}))
+			defer server.Close()
+			job.URL = server.URL + "/hook"
+
+			runs := runWebhook(t, NewScheduler(), job, 1)
+			assert.Equal(t, OutcomeSuccess, runs[0].Outcome)
+			assert.Equal(t, "200 OK\nok", runs[0].Output)
+
+			r := <-requests
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/hook", r.URL.Path)
+			assert.Equal(t, "acme", r.Header.Get("X-Tenant"))
+		})
+
+		t.Run("Retry unsuccessful status", func(t *testing.T) {
+			t.Parallel()
+			var counter int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&counter, 1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer server.Close()
+
+			schedule := NewScheduler(Config{RetryPolicy: RetryPolicy{MaxAttempts: 3, Delay: 10 * time.Millisecond}})
+			job := &WebhookJob{URL: server.URL, SuccessStatuses: []StatusRange{{Min: 202, Max: 202}}}
+			runs := runWebhook(t, schedule, job, 2)
+			assert.ErrorIs(t, runs[0].Err, ErrWebhookStatus)
+			assert.ErrorContains(t, runs[0].Err, "503 Service Unavailable")
+			assert.Equal(t, OutcomeSuccess, runs[1].Outcome)
+			assert.Equal(t, 2, runs[1].Attempt)
+		})
+	})
+
+	t.Run("Negative Case", func(t *testing.T) {
+		t.Run("Status out of the success ranges", func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			job := &WebhookJob{URL: server.URL, SuccessStatuses: []StatusRange{{Min: 204, Max: 204}}}
+			runs := runWebhook(t, NewScheduler(), job, 1)
+			assert.ErrorIs(t, runs[0].Err, ErrWebhookStatus)
+		})
+
+		t.Run("Timeout", func(t *testing.T) {
+			t.Parallel()
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer server.Close()
+			defer close(release)
+
+			job := &WebhookJob{URL: server.URL, Timeout: 50 * time.Millisecond}
+			runs := runWebhook(t, NewScheduler(), job, 1)
+			assert.ErrorIs(t, runs[0].Err, ErrWebhookFailed)
+		})
+
+		t.Run("Invalid body template", func(t *testing.T) {
+			t.Parallel()
+			job := &WebhookJob{URL: "http://127.0.0.1", Body: "{{.Unknown"}
+			runs := runWebhook(t, NewScheduler(), job, 1)
+			assert.ErrorIs(t, runs[0].Err, ErrWebhookFailed)
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+type yamlResponse struct {
+	config ListConfig
+}
+
+func (d *yamlResponse) Convert(data []*ResponseScheduler) ([]byte, error) {
+	return convertStream(d, data)
+}
+
+// Stream writes every job as a sequence of one item, the concatenation of
+// them is the sequence of every job.
+func (d *yamlResponse) Stream(w io.Writer, next ResponseIterator) (n int, err error) {
+	cw := &countWriter{w: w}
+	no := 0
+	for ; cw.err == nil; no++ {
+		res, ok := next()
+		if !ok {
+			break
+		}
+
+		var item []byte
+		if item, err = d.item(no+1, res); err != nil {
+			return cw.n, err
+		}
+
+		_, _ = cw.Write(item)
+	}
+
+	if no == 0 {
+		cw.WriteString("[]\n")
+	}
+	return cw.n, cw.err
+}
+
+func (d *yamlResponse) item(no int, data *ResponseScheduler) (res []byte, err error) {
+	item := &yaml.Node{Kind: yaml.MappingNode}
+	for _, column := range d.config.Columns {
+		var value interface{} = column.value(no, data, d.config.TimeFormat)
+		if column == ListColumnTags {
+			value = data.Tags
+		}
+
+		key, val := &yaml.Node{}, &yaml.Node{}
+		if err = key.Encode(listFields[column]); err != nil {
+			return
+		}
+
+		if err = val.Encode(value); err != nil {
+			return
+		}
+
+		item.Content = append(item.Content, key, val)
+	}
+
+	return yaml.Marshal(&yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{item}})
+}